@@ -5,13 +5,13 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"strings"
 
 	"github.com/Masterminds/squirrel"
+	"github.com/pboyd/godbmodels/common"
 )
 
-// ErrNotFound is returned when updating or deleting a character that does not
-// exist in the database.
+// ErrNotFound is returned when updating or deleting a character or scene that
+// does not exist in the database.
 var ErrNotFound = errors.New("not found")
 
 // Character is one character from the database.
@@ -23,12 +23,20 @@ type Character struct {
 
 // CharacterStore loads and updates characters in the database.
 type CharacterStore struct {
-	db *sql.DB
+	db      DBTX
+	dialect *common.Dialect
+	sb      squirrel.StatementBuilderType
 }
 
-// NewCharacterStore creates a new CharacterStore.
-func NewCharacterStore(db *sql.DB) *CharacterStore {
-	return &CharacterStore{db: db}
+// NewCharacterStore creates a new CharacterStore. db can be a *sql.DB or a
+// *sql.Tx.
+func NewCharacterStore(db DBTX, opts ...Option) *CharacterStore {
+	o := newOptions(opts)
+	return &CharacterStore{
+		db:      db,
+		dialect: o.dialect,
+		sb:      builder(db, o.dialect),
+	}
 }
 
 // Get loads a character from the database by ID.
@@ -36,11 +44,10 @@ func NewCharacterStore(db *sql.DB) *CharacterStore {
 // If no character is found, Get returns a nil Character and no error.
 func (cs *CharacterStore) Get(ctx context.Context, id int64) (*Character, error) {
 	var c Character
-	err := squirrel.
+	err := cs.sb.
 		Select("id", "actor_id", "name").
 		From("characters").
 		Where("id = ?", id).
-		RunWith(cs.db).
 		QueryRowContext(ctx).
 		Scan(&c.ID, &c.ActorID, &c.Name)
 
@@ -65,23 +72,32 @@ func (cs *CharacterStore) Store(ctx context.Context, c *Character) error {
 }
 
 func (cs *CharacterStore) insert(ctx context.Context, c *Character) error {
-	return squirrel.
+	// The dialect adds the placeholders and the RETURNING clause (if it
+	// has one), so the query is built with squirrel's defaults.
+	query, args, err := squirrel.
 		Insert("characters").
 		Columns("actor_id", "name").
 		Values(c.ActorID, c.Name).
-		Suffix("RETURNING id").
-		RunWith(cs.db).
-		QueryRowContext(ctx).
-		Scan(&c.ID)
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("insert character: %w", err)
+	}
+
+	id, err := cs.dialect.Insert(ctx, cs.db, query, args...)
+	if err != nil {
+		return fmt.Errorf("insert character: %w", err)
+	}
+
+	c.ID = id
+	return nil
 }
 
 func (cs *CharacterStore) update(ctx context.Context, c *Character) error {
-	res, err := squirrel.
+	res, err := cs.sb.
 		Update("characters").
 		Set("actor_id", c.ActorID).
 		Set("name", c.Name).
 		Where("id = ?", c.ID).
-		RunWith(cs.db).
 		ExecContext(ctx)
 	if err != nil {
 		return fmt.Errorf("update character: %w", err)
@@ -99,10 +115,9 @@ func (cs *CharacterStore) update(ctx context.Context, c *Character) error {
 //
 // If the character does not exist in the database, Delete returns ErrNotFound.
 func (cs *CharacterStore) Delete(ctx context.Context, id int64) error {
-	res, err := squirrel.
+	res, err := cs.sb.
 		Delete("characters").
 		Where("id = ?", id).
-		RunWith(cs.db).
 		ExecContext(ctx)
 	if err != nil {
 		return fmt.Errorf("delete character: %w", err)
@@ -129,29 +144,212 @@ type CharacterFilters struct {
 
 	// SceneNumber filters by the scene that the character appears in.
 	SceneNumber int64
+
+	// ActorIDs matches characters played by any of these actors.
+	ActorIDs []int64
+
+	// ExcludeActorIDs leaves out characters played by any of these actors.
+	ExcludeActorIDs []int64
+
+	// SceneNumbers matches characters that appear in any of these scenes,
+	// or in every one of them if AllScenes is set.
+	SceneNumbers []int64
+
+	// AllScenes makes SceneNumbers match characters that appear in every
+	// listed scene.
+	AllScenes bool
+
+	// SceneFrom and SceneTo match characters that appear in at least one
+	// scene in the range, inclusive. Either can be zero to leave that end
+	// of the range open.
+	SceneFrom int64
+	SceneTo   int64
+
+	// OrderBy sorts the results. List defaults to common.OrderByID and
+	// ListPage defaults to common.OrderByName.
+	OrderBy common.OrderBy
+
+	// Descending reverses the order.
+	Descending bool
 }
 
 // List searches for characters in the database.
 //
-// If filters is nil, all characters are returned. Otherwise, the results are
-// filtered by the criteria in filters.
+// If filters is nil, all characters are returned, in order of ID. Otherwise,
+// the results are filtered and sorted by the criteria in filters. If
+// filters.OrderBy is invalid, the error wraps common.ErrInvalidOrder.
 func (cs *CharacterStore) List(ctx context.Context, filters *CharacterFilters) ([]*Character, error) {
-	q := squirrel.
+	order, desc := filters.order(common.OrderByID)
+	orderBy, err := common.OrderClause("c", order, desc)
+	if err != nil {
+		return nil, err
+	}
+
+	return cs.list(ctx, cs.listQuery(filters).OrderBy(orderBy...))
+}
+
+// CharacterPage is one page of characters from ListPage.
+type CharacterPage struct {
+	Characters []*Character
+
+	// HasMore is true if there are characters after this page.
+	HasMore bool
+
+	// NextCursor continues from the last character on this page. It's empty
+	// if HasMore is false.
+	NextCursor string
+}
+
+// ListPage is like List, but returns one page of the results. The default
+// order is by name.
+//
+// If page is invalid, the error wraps common.ErrInvalidPage or
+// common.ErrInvalidCursor.
+func (cs *CharacterStore) ListPage(ctx context.Context, filters *CharacterFilters, page common.Page) (*CharacterPage, error) {
+	order, desc := filters.order(common.OrderByName)
+	orderBy, err := common.OrderClause("c", order, desc)
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := page.After(order, desc)
+	if err != nil {
+		return nil, err
+	}
+
+	// One extra row is fetched to find out if there's another page.
+	q := cs.listQuery(filters).
+		OrderBy(orderBy...).
+		Limit(uint64(page.Limit) + 1).
+		Offset(uint64(page.Offset))
+
+	if after != nil {
+		q = q.Where(squirrel.Or{
+			squirrel.Gt{"c.name": after.Name},
+			squirrel.And{
+				squirrel.Eq{"c.name": after.Name},
+				squirrel.Gt{"c.id": after.ID},
+			},
+		})
+	}
+
+	characters, err := cs.list(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &CharacterPage{}
+	p.Characters, p.HasMore, p.NextCursor = common.TrimPage(characters, page.Limit, func(c *Character) common.Cursor {
+		return common.Cursor{Name: c.Name, ID: c.ID}
+	})
+	return p, nil
+}
+
+// Count returns the number of characters that List would return for filters.
+// filters.OrderBy and Descending are ignored.
+func (cs *CharacterStore) Count(ctx context.Context, filters *CharacterFilters) (int, error) {
+	var n int
+	err := cs.sb.
+		Select("COUNT(*)").
+		FromSelect(cs.listQuery(filters), "l").
+		QueryRowContext(ctx).
+		Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("count characters: %w", err)
+	}
+
+	return n, nil
+}
+
+// CharacterList is a list of characters from ListWithFacets.
+type CharacterList struct {
+	Characters []*Character
+	Facets     common.Facets
+}
+
+// ListWithFacets is like List, but also counts the matching characters by
+// actor and by scene. The counts are grouped by the database.
+func (cs *CharacterStore) ListWithFacets(ctx context.Context, filters *CharacterFilters) (*CharacterList, error) {
+	characters, err := cs.List(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	actors, err := cs.facet(ctx, cs.sb.
+		Select("l.actor_id", "COUNT(*)").
+		FromSelect(cs.listQuery(filters), "l").
+		GroupBy("l.actor_id").
+		OrderBy("l.actor_id"))
+	if err != nil {
+		return nil, fmt.Errorf("count characters by actor: %w", err)
+	}
+
+	scenes, err := cs.facet(ctx, cs.sb.
+		Select("fsc.scene_id", "COUNT(*)").
+		FromSelect(cs.listQuery(filters), "l").
+		Join("scene_characters fsc ON fsc.character_id = l.id").
+		GroupBy("fsc.scene_id").
+		OrderBy("fsc.scene_id"))
+	if err != nil {
+		return nil, fmt.Errorf("count characters by scene: %w", err)
+	}
+
+	return &CharacterList{
+		Characters: characters,
+		Facets: common.Facets{
+			Actors: actors,
+			Scenes: scenes,
+		},
+	}, nil
+}
+
+// facet runs a query that returns IDs and counts.
+func (cs *CharacterStore) facet(ctx context.Context, q squirrel.SelectBuilder) ([]common.FacetCount, error) {
+	rows, err := q.QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var facets []common.FacetCount
+	for rows.Next() {
+		var f common.FacetCount
+		err := rows.Scan(&f.ID, &f.Count)
+		if err != nil {
+			return nil, err
+		}
+
+		facets = append(facets, f)
+	}
+
+	return facets, rows.Err()
+}
+
+// order returns the order the results are sorted in, which is def unless
+// filters says otherwise.
+func (filters *CharacterFilters) order(def common.OrderBy) (common.OrderBy, bool) {
+	if filters == nil {
+		return def, false
+	}
+	return filters.OrderBy.Or(def), filters.Descending
+}
+
+func (cs *CharacterStore) listQuery(filters *CharacterFilters) squirrel.SelectBuilder {
+	q := cs.sb.
 		Select("c.id", "c.actor_id", "c.name").
-		From("characters c").
-		RunWith(cs.db)
+		From("characters c")
 
 	if filters != nil {
 		if filters.ActorID != 0 {
-			q = q.Where("actor_id = ?", filters.ActorID)
+			q = q.Where("c.actor_id = ?", filters.ActorID)
 		} else if filters.ActorName != "" {
 			q = q.
 				Join("actors a ON a.id = c.actor_id").
-				Where("LOWER(a.name) LIKE ?", "%"+strings.ToLower(filters.ActorName)+"%")
+				Where(cs.dialect.ContainsFold("a.name", filters.ActorName))
 		}
 
 		if filters.Name != "" {
-			q = q.Where("LOWER(name) LIKE ?", "%"+strings.ToLower(filters.Name)+"%")
+			q = q.Where(cs.dialect.ContainsFold("c.name", filters.Name))
 		}
 
 		if filters.SceneNumber != 0 {
@@ -159,8 +357,54 @@ func (cs *CharacterStore) List(ctx context.Context, filters *CharacterFilters) (
 				Join("scene_characters sc ON sc.character_id = c.id").
 				Where("sc.scene_id = ?", filters.SceneNumber)
 		}
+
+		// squirrel.Eq would expand a slice to one placeholder per ID, which
+		// runs into SQLite's limit on parameters.
+		if len(filters.ActorIDs) > 0 {
+			q = q.Where(cs.dialect.InIDs("c.actor_id", filters.ActorIDs))
+		}
+
+		if len(filters.ExcludeActorIDs) > 0 {
+			cond, arg := cs.dialect.InIDs("c.actor_id", filters.ExcludeActorIDs)
+			q = q.Where("NOT "+cond, arg)
+		}
+
+		// The subqueries keep squirrel's default "?" placeholders, since
+		// the outer query rebinds them.
+		if len(filters.SceneNumbers) > 0 {
+			cond, arg := cs.dialect.InIDs("ssc.scene_id", filters.SceneNumbers)
+			sub := squirrel.
+				Select("ssc.character_id").
+				From("scene_characters ssc").
+				Where(cond, arg)
+			if filters.AllScenes {
+				// The character has to be in as many of the scenes as there
+				// are scenes in the list.
+				sub = sub.
+					GroupBy("ssc.character_id").
+					Having("COUNT(*) = ?", len(common.DistinctIDs(filters.SceneNumbers)))
+			}
+			q = q.Where(squirrel.Expr("c.id IN (?)", sub))
+		}
+
+		if filters.SceneFrom != 0 || filters.SceneTo != 0 {
+			sub := squirrel.
+				Select("rsc.character_id").
+				From("scene_characters rsc")
+			if filters.SceneFrom != 0 {
+				sub = sub.Where("rsc.scene_id >= ?", filters.SceneFrom)
+			}
+			if filters.SceneTo != 0 {
+				sub = sub.Where("rsc.scene_id <= ?", filters.SceneTo)
+			}
+			q = q.Where(squirrel.Expr("c.id IN (?)", sub))
+		}
 	}
 
+	return q
+}
+
+func (cs *CharacterStore) list(ctx context.Context, q squirrel.SelectBuilder) ([]*Character, error) {
 	rows, err := q.QueryContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("list characters: %w", err)
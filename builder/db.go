@@ -0,0 +1,92 @@
+package builder
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/pboyd/godbmodels/common"
+)
+
+// DBTX is implemented by both *sql.DB and *sql.Tx, so the stores can be used
+// inside a transaction.
+type DBTX interface {
+	squirrel.StdSqlCtx
+}
+
+// beginner is implemented by *sql.DB and *sql.Conn.
+type beginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// inTx calls fn with a transaction, which is committed if fn returns nil and
+// rolled back otherwise.
+//
+// If db can begin a transaction, it does. Otherwise db is already a
+// transaction, and fn runs inside a savepoint so its changes are undone on
+// error without ending the caller's transaction.
+func inTx(ctx context.Context, db DBTX, fn func(tx DBTX) error) error {
+	if b, ok := db.(beginner); ok {
+		tx, err := b.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+
+		err = fn(tx)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		return tx.Commit()
+	}
+
+	savepoint := fmt.Sprintf("builder_%d", savepointID.Add(1))
+	_, err := db.ExecContext(ctx, "SAVEPOINT "+savepoint)
+	if err != nil {
+		return err
+	}
+
+	err = fn(db)
+	if err != nil {
+		db.ExecContext(ctx, "ROLLBACK TO "+savepoint)
+		db.ExecContext(ctx, "RELEASE "+savepoint)
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, "RELEASE "+savepoint)
+	return err
+}
+
+var savepointID atomic.Int64
+
+// Option configures a store.
+type Option func(*options)
+
+type options struct {
+	dialect *common.Dialect
+}
+
+// WithDialect sets the SQL dialect the store writes queries in. The default
+// is common.SQLite.
+func WithDialect(d *common.Dialect) Option {
+	return func(o *options) {
+		o.dialect = d
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{dialect: common.SQLite}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// builder returns a squirrel statement builder that writes queries in the
+// dialect and runs them on db.
+func builder(db DBTX, d *common.Dialect) squirrel.StatementBuilderType {
+	return squirrel.StatementBuilder.PlaceholderFormat(d).RunWith(db)
+}
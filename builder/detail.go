@@ -0,0 +1,150 @@
+package builder
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// Actor is one actor from the database.
+type Actor struct {
+	ID   int64
+	Name string
+}
+
+// Quote is a line spoken by a character in a scene.
+type Quote struct {
+	ID          int64
+	CharacterID int64
+	SceneID     int64
+	Text        string
+}
+
+// Include selects the relations GetDetail loads.
+type Include uint
+
+const (
+	// IncludeActor loads CharacterDetail.Actor.
+	IncludeActor Include = 1 << iota
+
+	// IncludeScenes loads CharacterDetail.Scenes, in scene order.
+	IncludeScenes
+
+	// IncludeQuotes loads CharacterDetail.Quotes, in order of ID.
+	IncludeQuotes
+
+	// IncludeAll loads every relation.
+	IncludeAll = IncludeActor | IncludeScenes | IncludeQuotes
+)
+
+// CharacterDetail is a character along with the actor who plays them, the
+// scenes they appear in and the things they say.
+//
+// Relations that weren't requested are left nil.
+type CharacterDetail struct {
+	Character
+
+	Actor  *Actor
+	Scenes []*Scene
+	Quotes []*Quote
+}
+
+// GetDetail loads a character and the relations selected by include.
+//
+// It uses one query for the character and actor, plus one for scenes and one
+// for quotes when they're included.
+//
+// If no character is found, GetDetail returns a nil CharacterDetail and no
+// error.
+func (cs *CharacterStore) GetDetail(ctx context.Context, id int64, include Include) (*CharacterDetail, error) {
+	var d CharacterDetail
+	q := cs.sb.
+		Select("c.id", "c.actor_id", "c.name").
+		From("characters c").
+		Where("c.id = ?", id)
+	dest := []interface{}{&d.ID, &d.ActorID, &d.Name}
+
+	if include&IncludeActor != 0 {
+		d.Actor = &Actor{}
+		q = q.Columns("a.id", "a.name").Join("actors a ON a.id = c.actor_id")
+		dest = append(dest, &d.Actor.ID, &d.Actor.Name)
+	}
+
+	err := q.QueryRowContext(ctx).Scan(dest...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get character detail: %w", err)
+	}
+
+	if include&IncludeScenes != 0 {
+		d.Scenes, err = cs.characterScenes(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("get character detail: %w", err)
+		}
+	}
+
+	if include&IncludeQuotes != 0 {
+		d.Quotes, err = cs.characterQuotes(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("get character detail: %w", err)
+		}
+	}
+
+	return &d, nil
+}
+
+func (cs *CharacterStore) characterScenes(ctx context.Context, id int64) ([]*Scene, error) {
+	rows, err := cs.sb.
+		Select("s.id", "s.name").
+		From("scenes s").
+		Join("scene_characters sc ON sc.scene_id = s.id").
+		Where("sc.character_id = ?", id).
+		OrderBy("s.id").
+		QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	scenes := []*Scene{}
+	for rows.Next() {
+		var s Scene
+		err := rows.Scan(&s.ID, &s.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		scenes = append(scenes, &s)
+	}
+
+	return scenes, rows.Err()
+}
+
+func (cs *CharacterStore) characterQuotes(ctx context.Context, id int64) ([]*Quote, error) {
+	rows, err := cs.sb.
+		Select("id", "character_id", "scene_id", "text").
+		From("quotes").
+		Where("character_id = ?", id).
+		OrderBy("id").
+		QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	quotes := []*Quote{}
+	for rows.Next() {
+		var q Quote
+		err := rows.Scan(&q.ID, &q.CharacterID, &q.SceneID, &q.Text)
+		if err != nil {
+			return nil, err
+		}
+
+		quotes = append(quotes, &q)
+	}
+
+	return quotes, rows.Err()
+}
@@ -0,0 +1,70 @@
+package builder
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pboyd/godbmodels/common"
+	"github.com/pboyd/godbmodels/common/sqltest"
+)
+
+func TestDialects(t *testing.T) {
+	for _, d := range common.Dialects {
+		d := d
+		t.Run(d.Name, func(t *testing.T) {
+			ctx := context.Background()
+			db, rec := sqltest.Open(t)
+			cs := NewCharacterStore(db, WithDialect(d))
+
+			rec.Section("Get")
+			cs.Get(ctx, 1)
+
+			rec.Section("Insert")
+			cs.Store(ctx, &Character{ActorID: 1, Name: "Sir Not-Appearing-in-this-Film"})
+
+			rec.Section("Update")
+			cs.Store(ctx, &Character{ID: 82, ActorID: 2, Name: "Sir Maybe-Appearing-in-this-Film"})
+
+			rec.Section("Delete")
+			cs.Delete(ctx, 82)
+
+			rec.Section("List")
+			cs.List(ctx, nil)
+			cs.List(ctx, &CharacterFilters{ActorID: 3, Name: "Brother", SceneNumber: 3})
+			cs.List(ctx, &CharacterFilters{ActorName: "Sandy", Name: "Knight"})
+			cs.List(ctx, &CharacterFilters{Name: "Knight", OrderBy: common.OrderByActorName, Descending: true})
+			cs.List(ctx, &CharacterFilters{ActorIDs: []int64{1, 2}, ExcludeActorIDs: []int64{3}, SceneNumbers: []int64{1, 3}, AllScenes: true, SceneFrom: 10, SceneTo: 15})
+			cs.List(ctx, &CharacterFilters{SceneNumbers: []int64{1, 3}, SceneTo: 15})
+
+			rec.Section("List Page")
+			cs.ListPage(ctx, &CharacterFilters{ActorID: 3}, common.Page{Limit: 10, Offset: 20})
+			cs.ListPage(ctx, &CharacterFilters{SceneNumber: 3}, common.Page{Limit: 10, Cursor: common.EncodeCursor(common.Cursor{Name: "Dennis", ID: 20})})
+
+			rec.Section("Count")
+			cs.Count(ctx, &CharacterFilters{ActorName: "Palin", SceneNumber: 3})
+
+			rec.Section("List With Facets")
+			cs.ListWithFacets(ctx, &CharacterFilters{Name: "Knight"})
+
+			ss := NewSceneStore(db, WithDialect(d))
+
+			rec.Section("Scene Insert")
+			ss.Store(ctx, &Scene{Name: "The Black Beast of Aaaaargh"})
+
+			rec.Section("Scene Update")
+			ss.Store(ctx, &Scene{ID: 24, Name: "The Cartoon Peril"})
+
+			rec.Section("Scene Delete")
+			ss.Delete(ctx, 24)
+
+			rec.Section("Scene List")
+			ss.List(ctx)
+			ss.ListCharacters(ctx, 3)
+
+			rec.Section("Scene SetCharacters")
+			ss.SetCharacters(ctx, 3, []int64{1, 2})
+
+			sqltest.Golden(t, "dialect_"+d.Name, rec.String())
+		})
+	}
+}
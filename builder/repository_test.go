@@ -0,0 +1,20 @@
+package builder
+
+import (
+	"testing"
+
+	"github.com/pboyd/godbmodels/common"
+	"github.com/pboyd/godbmodels/common/repotest"
+)
+
+func TestCharacterRepository(t *testing.T) {
+	repotest.TestCharacterRepository(t, func(t testing.TB) common.CharacterRepository {
+		return NewCharacterRepository(NewCharacterStore(common.TestTx(t)))
+	})
+}
+
+func BenchmarkCharacterRepository(b *testing.B) {
+	repotest.BenchmarkCharacterRepository(b, func(t testing.TB) common.CharacterRepository {
+		return NewCharacterRepository(NewCharacterStore(common.TestDB(t)))
+	})
+}
@@ -0,0 +1,343 @@
+package builder
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/pboyd/godbmodels/common"
+)
+
+// Scene is one scene from the database. The ID is the scene number.
+type Scene struct {
+	ID   int64
+	Name string
+}
+
+// SceneStore loads and updates scenes, and the characters that appear in
+// them.
+type SceneStore struct {
+	db      DBTX
+	dialect *common.Dialect
+	sb      squirrel.StatementBuilderType
+}
+
+// NewSceneStore creates a new SceneStore. db can be a *sql.DB or a *sql.Tx.
+func NewSceneStore(db DBTX, opts ...Option) *SceneStore {
+	o := newOptions(opts)
+	return &SceneStore{
+		db:      db,
+		dialect: o.dialect,
+		sb:      builder(db, o.dialect),
+	}
+}
+
+// withTx returns a SceneStore that uses tx.
+func (ss *SceneStore) withTx(tx DBTX) *SceneStore {
+	return NewSceneStore(tx, WithDialect(ss.dialect))
+}
+
+// Get loads a scene from the database by ID.
+//
+// If no scene is found, Get returns a nil Scene and no error.
+func (ss *SceneStore) Get(ctx context.Context, id int64) (*Scene, error) {
+	var s Scene
+	err := ss.sb.
+		Select("id", "name").
+		From("scenes").
+		Where("id = ?", id).
+		QueryRowContext(ctx).
+		Scan(&s.ID, &s.Name)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	return &s, err
+}
+
+// Store saves a scene to the database. If the scene has an ID, it will be
+// updated. Otherwise, it will be inserted with the next scene number, and the
+// ID will be set.
+//
+// If the scene has an ID and it does not exist in the database, Store returns
+// ErrNotFound.
+func (ss *SceneStore) Store(ctx context.Context, s *Scene) error {
+	if s.ID == 0 {
+		return ss.insert(ctx, s)
+	}
+
+	return ss.update(ctx, s)
+}
+
+func (ss *SceneStore) insert(ctx context.Context, s *Scene) error {
+	query, args, err := squirrel.
+		Insert("scenes").
+		Columns("name").
+		Values(s.Name).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("insert scene: %w", err)
+	}
+
+	id, err := ss.dialect.Insert(ctx, ss.db, query, args...)
+	if err != nil {
+		return fmt.Errorf("insert scene: %w", err)
+	}
+
+	s.ID = id
+	return nil
+}
+
+func (ss *SceneStore) update(ctx context.Context, s *Scene) error {
+	res, err := ss.sb.
+		Update("scenes").
+		Set("name", s.Name).
+		Where("id = ?", s.ID).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("update scene: %w", err)
+	}
+
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// Delete removes a scene from the database, along with its quotes and the
+// record of which characters appear in it. The characters themselves are
+// not deleted.
+//
+// If the scene does not exist in the database, Delete returns ErrNotFound.
+func (ss *SceneStore) Delete(ctx context.Context, id int64) error {
+	err := inTx(ctx, ss.db, func(tx DBTX) error {
+		sb := builder(tx, ss.dialect)
+
+		_, err := sb.Delete("quotes").Where("scene_id = ?", id).ExecContext(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = sb.Delete("scene_characters").Where("scene_id = ?", id).ExecContext(ctx)
+		if err != nil {
+			return err
+		}
+
+		res, err := sb.Delete("scenes").Where("id = ?", id).ExecContext(ctx)
+		if err != nil {
+			return err
+		}
+
+		rows, _ := res.RowsAffected()
+		if rows == 0 {
+			return ErrNotFound
+		}
+
+		return nil
+	})
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("delete scene: %w", err)
+	}
+
+	return err
+}
+
+// List returns every scene, in order.
+func (ss *SceneStore) List(ctx context.Context) ([]*Scene, error) {
+	rows, err := ss.sb.
+		Select("id", "name").
+		From("scenes").
+		OrderBy("id").
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list scenes: %w", err)
+	}
+	defer rows.Close()
+
+	var scenes []*Scene
+	for rows.Next() {
+		var s Scene
+		err := rows.Scan(&s.ID, &s.Name)
+		if err != nil {
+			return nil, fmt.Errorf("list scenes: %w", err)
+		}
+
+		scenes = append(scenes, &s)
+	}
+
+	return scenes, nil
+}
+
+// ListCharacters returns the characters that appear in a scene, ordered by
+// ID.
+func (ss *SceneStore) ListCharacters(ctx context.Context, sceneID int64) ([]*Character, error) {
+	rows, err := ss.sb.
+		Select("c.id", "c.actor_id", "c.name").
+		From("characters c").
+		Join("scene_characters sc ON sc.character_id = c.id").
+		Where("sc.scene_id = ?", sceneID).
+		OrderBy("c.id").
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list scene characters: %w", err)
+	}
+	defer rows.Close()
+
+	var characters []*Character
+	for rows.Next() {
+		var c Character
+		err := rows.Scan(&c.ID, &c.ActorID, &c.Name)
+		if err != nil {
+			return nil, fmt.Errorf("list scene characters: %w", err)
+		}
+
+		characters = append(characters, &c)
+	}
+
+	return characters, nil
+}
+
+// AddCharacters adds characters to a scene. Characters that are already in
+// the scene are skipped.
+//
+// If the scene does not exist in the database, AddCharacters returns
+// ErrNotFound.
+func (ss *SceneStore) AddCharacters(ctx context.Context, sceneID int64, characterIDs ...int64) error {
+	return ss.edit(ctx, "add scene characters", sceneID, func(current map[int64]bool) (add, remove []int64) {
+		for _, id := range characterIDs {
+			if !current[id] {
+				add = append(add, id)
+				current[id] = true
+			}
+		}
+		return add, nil
+	})
+}
+
+// RemoveCharacters removes characters from a scene. Characters that aren't
+// in the scene are skipped.
+//
+// If the scene does not exist in the database, RemoveCharacters returns
+// ErrNotFound.
+func (ss *SceneStore) RemoveCharacters(ctx context.Context, sceneID int64, characterIDs ...int64) error {
+	return ss.edit(ctx, "remove scene characters", sceneID, func(current map[int64]bool) (add, remove []int64) {
+		for _, id := range characterIDs {
+			if current[id] {
+				remove = append(remove, id)
+				delete(current, id)
+			}
+		}
+		return nil, remove
+	})
+}
+
+// SetCharacters changes the characters in a scene to exactly characterIDs,
+// adding and removing characters as needed.
+//
+// If the scene does not exist in the database, SetCharacters returns
+// ErrNotFound.
+func (ss *SceneStore) SetCharacters(ctx context.Context, sceneID int64, characterIDs []int64) error {
+	return ss.edit(ctx, "set scene characters", sceneID, func(current map[int64]bool) (add, remove []int64) {
+		want := map[int64]bool{}
+		for _, id := range characterIDs {
+			if !want[id] && !current[id] {
+				add = append(add, id)
+			}
+			want[id] = true
+		}
+
+		for id := range current {
+			if !want[id] {
+				remove = append(remove, id)
+			}
+		}
+		sort.Slice(remove, func(i, j int) bool { return remove[i] < remove[j] })
+
+		return add, remove
+	})
+}
+
+// characterIDs returns the IDs in scene_characters for a scene. Unlike
+// ListCharacters, it includes rows for characters that no longer exist.
+func (ss *SceneStore) characterIDs(ctx context.Context, sceneID int64) (map[int64]bool, error) {
+	rows, err := ss.sb.
+		Select("character_id").
+		From("scene_characters").
+		Where("scene_id = ?", sceneID).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := map[int64]bool{}
+	for rows.Next() {
+		var id int64
+		err := rows.Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+
+		ids[id] = true
+	}
+
+	return ids, rows.Err()
+}
+
+// edit loads the characters in a scene and applies the changes returned by
+// diff, all in one transaction.
+func (ss *SceneStore) edit(ctx context.Context, op string, sceneID int64, diff func(current map[int64]bool) (add, remove []int64)) error {
+	err := inTx(ctx, ss.db, func(tx DBTX) error {
+		store := ss.withTx(tx)
+
+		s, err := store.Get(ctx, sceneID)
+		if err != nil {
+			return err
+		}
+		if s == nil {
+			return ErrNotFound
+		}
+
+		current, err := store.characterIDs(ctx, sceneID)
+		if err != nil {
+			return err
+		}
+
+		add, remove := diff(current)
+
+		if len(remove) > 0 {
+			_, err := store.sb.
+				Delete("scene_characters").
+				Where(squirrel.Eq{"scene_id": sceneID, "character_id": remove}).
+				ExecContext(ctx)
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(add) > 0 {
+			q := store.sb.Insert("scene_characters").Columns("scene_id", "character_id")
+			for _, id := range add {
+				q = q.Values(sceneID, id)
+			}
+
+			_, err := q.ExecContext(ctx)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return err
+}
@@ -0,0 +1,226 @@
+package builder
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pboyd/godbmodels/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScenes(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	ss := NewSceneStore(common.TestTx(t))
+	s := &Scene{Name: "The Black Beast of Aaaaargh"}
+
+	// Create
+	err := ss.Store(ctx, s)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Equal(int64(24), s.ID)
+
+	// Read
+	s2, err := ss.Get(ctx, s.ID)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Equal(s, s2)
+
+	// Update
+	s.Name = "The Cartoon Peril"
+	err = ss.Store(ctx, s)
+	if !assert.NoError(err) {
+		return
+	}
+	s2, err = ss.Get(ctx, s.ID)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Equal(s, s2)
+
+	scenes, err := ss.List(ctx)
+	if assert.NoError(err) && assert.Len(scenes, 24) {
+		assert.Equal(int64(1), scenes[0].ID)
+		assert.Equal(s, scenes[23])
+	}
+
+	// Delete
+	err = ss.Delete(ctx, s.ID)
+	if !assert.NoError(err) {
+		return
+	}
+	s2, err = ss.Get(ctx, s.ID)
+	if assert.NoError(err) {
+		assert.Nil(s2)
+	}
+
+	// Delete again
+	err = ss.Delete(ctx, s.ID)
+	assert.Equal(ErrNotFound, err)
+
+	// Update missing
+	err = ss.Store(ctx, s)
+	assert.Equal(ErrNotFound, err)
+}
+
+func TestDeleteSceneWithCharacters(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	tx := common.TestTx(t)
+
+	err := NewSceneStore(tx).Delete(ctx, 3)
+	if !assert.NoError(err) {
+		return
+	}
+
+	var quotes, appearances int
+	err = tx.QueryRow(`SELECT (SELECT COUNT(*) FROM quotes WHERE scene_id = 3), (SELECT COUNT(*) FROM scene_characters WHERE scene_id = 3)`).Scan(&quotes, &appearances)
+	if assert.NoError(err) {
+		assert.Zero(quotes)
+		assert.Zero(appearances)
+	}
+
+	// The characters are still there.
+	c, err := NewCharacterStore(tx).Get(ctx, 34)
+	if assert.NoError(err) {
+		assert.NotNil(c)
+	}
+}
+
+func TestSceneCharacters(t *testing.T) {
+	ctx := context.Background()
+	tx := common.TestTx(t)
+	ss := NewSceneStore(tx)
+
+	// Scene 3 has King Arthur, Patsy, Dennis's Mother and Dennis.
+	const scene = 3
+	original := []int64{1, 20, 27, 34}
+
+	cases := map[string]struct {
+		edit     func() error
+		expected []int64
+
+		// err is the expected error. If it's nil and fails is true, any
+		// error will do.
+		err   error
+		fails bool
+	}{
+		"Add": {
+			edit:     func() error { return ss.AddCharacters(ctx, scene, 34, 2, 3, 2) },
+			expected: []int64{1, 2, 3, 20, 27, 34},
+		},
+		"Remove": {
+			edit:     func() error { return ss.RemoveCharacters(ctx, scene, 20, 2, 27) },
+			expected: []int64{1, 34},
+		},
+		"Set": {
+			edit:     func() error { return ss.SetCharacters(ctx, scene, []int64{2, 34, 1, 2}) },
+			expected: []int64{1, 2, 34},
+		},
+		"SetNone": {
+			edit:     func() error { return ss.SetCharacters(ctx, scene, nil) },
+			expected: nil,
+		},
+		"MissingScene": {
+			edit:     func() error { return ss.SetCharacters(ctx, 9999, []int64{1}) },
+			expected: original,
+			err:      ErrNotFound,
+		},
+		"MissingCharacter": {
+			// The other characters are removed before adding 9999 fails,
+			// so this checks that the whole change is rolled back.
+			edit:     func() error { return ss.SetCharacters(ctx, scene, []int64{2, 9999}) },
+			expected: original,
+			fails:    true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			common.TestSavepoint(t, tx)
+
+			err := tc.edit()
+			switch {
+			case tc.err != nil:
+				assert.ErrorIs(err, tc.err)
+			case tc.fails:
+				assert.Error(err)
+			default:
+				if !assert.NoError(err) {
+					return
+				}
+			}
+
+			assert.Equal(tc.expected, sceneCharacterIDs(t, ss, scene))
+		})
+	}
+}
+
+func TestSetCharactersDB(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	// A *sql.DB instead of a transaction, so SetCharacters starts its own.
+	ss := NewSceneStore(common.TestDB(t))
+
+	err := ss.SetCharacters(ctx, 3, []int64{2, 9999})
+	assert.Error(err)
+	assert.Equal([]int64{1, 20, 27, 34}, sceneCharacterIDs(t, ss, 3))
+
+	err = ss.SetCharacters(ctx, 3, []int64{34, 2})
+	if assert.NoError(err) {
+		assert.Equal([]int64{2, 34}, sceneCharacterIDs(t, ss, 3))
+	}
+}
+
+func TestSetCharactersOrphaned(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	db := common.TestDB(t)
+	ss := NewSceneStore(db)
+
+	// A row for a character that doesn't exist, which ListCharacters can't
+	// see.
+	_, err := db.Exec(`PRAGMA foreign_keys = OFF`)
+	if !assert.NoError(err) {
+		return
+	}
+	_, err = db.Exec(`INSERT INTO scene_characters (scene_id, character_id) VALUES (3, 9999)`)
+	if !assert.NoError(err) {
+		return
+	}
+	_, err = db.Exec(`PRAGMA foreign_keys = ON`)
+	if !assert.NoError(err) {
+		return
+	}
+
+	err = ss.SetCharacters(ctx, 3, []int64{1, 20, 27, 34})
+	if !assert.NoError(err) {
+		return
+	}
+
+	var n int
+	err = db.QueryRow(`SELECT COUNT(*) FROM scene_characters WHERE scene_id = 3`).Scan(&n)
+	if assert.NoError(err) {
+		assert.Equal(4, n)
+	}
+}
+
+func sceneCharacterIDs(t *testing.T, ss *SceneStore, sceneID int64) []int64 {
+	t.Helper()
+
+	characters, err := ss.ListCharacters(context.Background(), sceneID)
+	if err != nil {
+		t.Fatalf("Error listing scene characters: %s", err)
+	}
+
+	var ids []int64
+	for _, c := range characters {
+		ids = append(ids, c.ID)
+	}
+	return ids
+}
@@ -0,0 +1,3 @@
+module github.com/pboyd/godbmodels/cmd/benchreport
+
+go 1.20
@@ -0,0 +1,222 @@
+// Command benchreport runs the CharacterRepository benchmarks for every model
+// package and prints the results as markdown tables.
+//
+// Usage:
+//
+//	benchreport [-root dir] [-benchtime d] [-in file]
+//
+// With -in, benchreport reads saved `go test -bench` output instead of
+// running the benchmarks.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// packages are the model packages, in the order they appear in the report.
+var packages = []string{"vanilla", "builder", "mapper", "sqlc", "orm"}
+
+// metrics are the benchmark units that are reported, in order.
+var metrics = []string{"ns/op", "allocs/op", "B/op"}
+
+func main() {
+	root := flag.String("root", "../..", "path to the repository root")
+	benchtime := flag.String("benchtime", "1s", "passed to go test -benchtime")
+	in := flag.String("in", "", "read benchmark output from this file (- for stdin) instead of running the benchmarks")
+	flag.Parse()
+
+	var (
+		output []byte
+		err    error
+	)
+	switch *in {
+	case "":
+		output, err = runBenchmarks(*root, *benchtime)
+	case "-":
+		output, err = io.ReadAll(os.Stdin)
+	default:
+		output, err = os.ReadFile(*in)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "benchreport: %s\n", err)
+		os.Exit(1)
+	}
+
+	results, err := parse(bytes.NewReader(output))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "benchreport: %s\n", err)
+		os.Exit(1)
+	}
+
+	report(os.Stdout, results)
+}
+
+// runBenchmarks runs the benchmarks in each package and returns the combined
+// output.
+func runBenchmarks(root, benchtime string) ([]byte, error) {
+	var output bytes.Buffer
+
+	for _, pkg := range packages {
+		fmt.Fprintf(os.Stderr, "benchmarking %s\n", pkg)
+
+		cmd := exec.Command("go", "test", "-run", "^$", "-bench", "BenchmarkCharacterRepository", "-benchmem", "-benchtime", benchtime, ".")
+		cmd.Dir = filepath.Join(root, pkg)
+		cmd.Stdout = &output
+		cmd.Stderr = os.Stderr
+
+		err := cmd.Run()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", pkg, err)
+		}
+	}
+
+	return output.Bytes(), nil
+}
+
+// results holds benchmark values by benchmark name, then package, then unit.
+type results struct {
+	// names are the benchmark names in the order they were first seen.
+	names []string
+
+	values map[string]map[string]map[string]string
+}
+
+func (r *results) add(name, pkg, unit, value string) {
+	if r.values == nil {
+		r.values = map[string]map[string]map[string]string{}
+	}
+
+	byPkg, ok := r.values[name]
+	if !ok {
+		byPkg = map[string]map[string]string{}
+		r.values[name] = byPkg
+		r.names = append(r.names, name)
+	}
+
+	byUnit, ok := byPkg[pkg]
+	if !ok {
+		byUnit = map[string]string{}
+		byPkg[pkg] = byUnit
+	}
+
+	byUnit[unit] = value
+}
+
+// parse reads `go test -bench` output. The package for each benchmark is
+// taken from the preceding "pkg:" line.
+func parse(r io.Reader) (*results, error) {
+	res := &results{}
+	pkg := ""
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := s.Text()
+
+		if strings.HasPrefix(line, "pkg: ") {
+			pkg = path.Base(strings.TrimSpace(strings.TrimPrefix(line, "pkg: ")))
+			continue
+		}
+
+		if !strings.HasPrefix(line, "Benchmark") {
+			continue
+		}
+
+		// BenchmarkName-8  1000  1234 ns/op  56 B/op  7 allocs/op
+		fields := strings.Fields(line)
+		if len(fields) < 4 || len(fields)%2 != 0 {
+			continue
+		}
+		if _, err := strconv.Atoi(fields[1]); err != nil {
+			continue
+		}
+
+		name := benchmarkName(fields[0])
+		for i := 2; i < len(fields); i += 2 {
+			res.add(name, pkg, fields[i+1], fields[i])
+		}
+	}
+
+	return res, s.Err()
+}
+
+// benchmarkName strips the Benchmark prefix, the top-level benchmark name and
+// the GOMAXPROCS suffix from a benchmark name.
+func benchmarkName(name string) string {
+	if i := strings.LastIndexByte(name, '-'); i > 0 {
+		if _, err := strconv.Atoi(name[i+1:]); err == nil {
+			name = name[:i]
+		}
+	}
+
+	if i := strings.IndexByte(name, '/'); i > 0 {
+		return name[i+1:]
+	}
+
+	return strings.TrimPrefix(name, "Benchmark")
+}
+
+// report writes one markdown table for each metric with a column for each
+// package.
+func report(w io.Writer, res *results) {
+	pkgs := reportPackages(res)
+
+	for i, unit := range metrics {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+
+		fmt.Fprintf(w, "### %s\n\n", unit)
+		fmt.Fprintf(w, "| Benchmark | %s |\n", strings.Join(pkgs, " | "))
+		fmt.Fprintf(w, "|---|%s\n", strings.Repeat("---:|", len(pkgs)))
+
+		for _, name := range res.names {
+			fmt.Fprintf(w, "| %s |", name)
+			for _, pkg := range pkgs {
+				value := res.values[name][pkg][unit]
+				if value == "" {
+					value = "-"
+				}
+				fmt.Fprintf(w, " %s |", value)
+			}
+			fmt.Fprintln(w)
+		}
+	}
+}
+
+// reportPackages returns the packages that appear in res. The model packages
+// come first in their usual order, followed by any others.
+func reportPackages(res *results) []string {
+	seen := map[string]bool{}
+	for _, byPkg := range res.values {
+		for pkg := range byPkg {
+			seen[pkg] = true
+		}
+	}
+
+	var pkgs []string
+	for _, pkg := range packages {
+		if seen[pkg] {
+			pkgs = append(pkgs, pkg)
+			delete(seen, pkg)
+		}
+	}
+
+	var others []string
+	for pkg := range seen {
+		others = append(others, pkg)
+	}
+	sort.Strings(others)
+
+	return append(pkgs, others...)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const sampleOutput = `goos: linux
+goarch: amd64
+pkg: github.com/pboyd/godbmodels/vanilla
+BenchmarkCharacterRepository/Get-8         	  200	      6568 ns/op	     798 B/op	      26 allocs/op
+BenchmarkCharacterRepository/List/ActorID+Name-8 	  200	     14894 ns/op	    1104 B/op	      34 allocs/op
+PASS
+ok  	github.com/pboyd/godbmodels/vanilla	0.124s
+pkg: github.com/pboyd/godbmodels/orm
+BenchmarkCharacterRepository/Get-8         	  100	     16000 ns/op	    4000 B/op	      60 allocs/op
+PASS
+`
+
+const expectedReport = `### ns/op
+
+| Benchmark | vanilla | orm |
+|---|---:|---:|
+| Get | 6568 | 16000 |
+| List/ActorID+Name | 14894 | - |
+
+### allocs/op
+
+| Benchmark | vanilla | orm |
+|---|---:|---:|
+| Get | 26 | 60 |
+| List/ActorID+Name | 34 | - |
+
+### B/op
+
+| Benchmark | vanilla | orm |
+|---|---:|---:|
+| Get | 798 | 4000 |
+| List/ActorID+Name | 1104 | - |
+`
+
+func TestReport(t *testing.T) {
+	res, err := parse(strings.NewReader(sampleOutput))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	report(&buf, res)
+
+	if buf.String() != expectedReport {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), expectedReport)
+	}
+}
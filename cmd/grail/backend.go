@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/pboyd/godbmodels/builder"
+	"github.com/pboyd/godbmodels/common"
+	"github.com/pboyd/godbmodels/mapper"
+	"github.com/pboyd/godbmodels/orm"
+	"github.com/pboyd/godbmodels/sqlc"
+	"github.com/pboyd/godbmodels/vanilla"
+)
+
+const backendNames = "vanilla, builder, mapper, sqlc or orm"
+
+// openDB opens the database named by the --db flag, creating and migrating
+// it if necessary.
+func openDB(g *globals) (*sql.DB, error) {
+	db, err := common.Open(g.dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", g.dbPath, err)
+	}
+
+	return db, nil
+}
+
+// characterRepository returns the CharacterRepository for the backend named
+// by the --backend flag.
+func characterRepository(g *globals, db *sql.DB) (common.CharacterRepository, error) {
+	switch g.backend {
+	case "vanilla":
+		return vanilla.NewCharacterRepository(vanilla.NewCharacterStore(db)), nil
+	case "builder":
+		return builder.NewCharacterRepository(builder.NewCharacterStore(db)), nil
+	case "mapper":
+		return mapper.NewCharacterRepository(mapper.NewCharacterStore(db)), nil
+	case "sqlc":
+		return sqlc.NewCharacterRepository(sqlc.NewCharacterStore(db)), nil
+	case "orm":
+		gdb, err := orm.Open(db)
+		if err != nil {
+			return nil, err
+		}
+		return orm.NewCharacterRepository(gdb), nil
+	default:
+		return nil, fmt.Errorf("unknown backend %q, must be one of %s", g.backend, backendNames)
+	}
+}
+
+// actorRow, sceneRow and quoteRow are the rows the actors, scenes and quotes
+// commands show, whichever backend loaded them.
+type actorRow struct {
+	id   int64
+	name string
+}
+
+type sceneRow struct {
+	id   int64
+	name string
+}
+
+type quoteRow struct {
+	id, sceneID, characterID int64
+	text                     string
+}
+
+// tableStores are the actor, scene and quote queries of the backend named by
+// the --backend flag. Not every model package has a store for every table,
+// so a func is nil if the backend can't run it.
+type tableStores struct {
+	backend    string
+	characters common.CharacterRepository
+
+	actors func(ctx context.Context, name string) ([]actorRow, error)
+	scenes func(ctx context.Context) ([]sceneRow, error)
+	quotes func(ctx context.Context, characterID, sceneID int64) ([]quoteRow, error)
+}
+
+// unsupported returns the error for a table the backend has no store for.
+func (ts *tableStores) unsupported(table string) error {
+	return fmt.Errorf("%s are not supported by backend %s", table, ts.backend)
+}
+
+func newTableStores(g *globals, db *sql.DB) (*tableStores, error) {
+	repo, err := characterRepository(g, db)
+	if err != nil {
+		return nil, err
+	}
+	ts := &tableStores{backend: g.backend, characters: repo}
+
+	switch g.backend {
+	case "vanilla":
+		as := vanilla.NewActorStore(db)
+		ts.actors = func(ctx context.Context, name string) ([]actorRow, error) {
+			list, err := as.List(ctx, &vanilla.ActorFilters{Name: name})
+			rows := make([]actorRow, len(list))
+			for i, a := range list {
+				rows[i] = actorRow{id: a.ID, name: a.Name}
+			}
+			return rows, err
+		}
+	case "builder":
+		ss := builder.NewSceneStore(db)
+		ts.scenes = func(ctx context.Context) ([]sceneRow, error) {
+			list, err := ss.List(ctx)
+			rows := make([]sceneRow, len(list))
+			for i, s := range list {
+				rows[i] = sceneRow{id: s.ID, name: s.Name}
+			}
+			return rows, err
+		}
+	case "mapper":
+		qs := mapper.NewQuoteStore(db)
+		ts.quotes = func(ctx context.Context, characterID, sceneID int64) ([]quoteRow, error) {
+			list, err := qs.List(ctx, &mapper.QuoteFilters{CharacterID: characterID, SceneNumber: sceneID})
+			rows := make([]quoteRow, len(list))
+			for i, q := range list {
+				rows[i] = quoteRow{id: q.ID, sceneID: q.Scene.ID, characterID: q.Character.ID, text: q.Text}
+			}
+			return rows, err
+		}
+	case "sqlc":
+		q := sqlc.New(db)
+		ts.actors = func(ctx context.Context, name string) ([]actorRow, error) {
+			list, err := q.ListActors(ctx, &sqlc.ActorFilters{Name: name})
+			rows := make([]actorRow, len(list))
+			for i, a := range list {
+				rows[i] = actorRow{id: a.ID, name: a.Name}
+			}
+			return rows, err
+		}
+		ts.scenes = func(ctx context.Context) ([]sceneRow, error) {
+			list, err := q.ListScenes(ctx)
+			rows := make([]sceneRow, len(list))
+			for i, s := range list {
+				rows[i] = sceneRow{id: s.ID, name: s.Name}
+			}
+			return rows, err
+		}
+		ts.quotes = func(ctx context.Context, characterID, sceneID int64) ([]quoteRow, error) {
+			list, err := q.ListQuotes(ctx, &sqlc.QuoteFilters{CharacterID: characterID, SceneNumber: sceneID})
+			rows := make([]quoteRow, len(list))
+			for i, q := range list {
+				rows[i] = quoteRow{id: q.ID, sceneID: q.SceneID, characterID: q.CharacterID, text: q.Text}
+			}
+			return rows, err
+		}
+	case "orm":
+		gdb, err := orm.Open(db)
+		if err != nil {
+			return nil, err
+		}
+		ts.actors = func(ctx context.Context, name string) ([]actorRow, error) {
+			var list []orm.Actor
+			query := gdb.WithContext(ctx).Order("id")
+			if name != "" {
+				query = query.Where("LOWER(name) LIKE ?", "%"+strings.ToLower(name)+"%")
+			}
+			err := query.Find(&list).Error
+			rows := make([]actorRow, len(list))
+			for i, a := range list {
+				rows[i] = actorRow{id: a.ID, name: a.Name}
+			}
+			return rows, err
+		}
+		ts.scenes = func(ctx context.Context) ([]sceneRow, error) {
+			var list []orm.Scene
+			err := gdb.WithContext(ctx).Order("id").Find(&list).Error
+			rows := make([]sceneRow, len(list))
+			for i, s := range list {
+				rows[i] = sceneRow{id: s.ID, name: s.Name}
+			}
+			return rows, err
+		}
+		ts.quotes = func(ctx context.Context, characterID, sceneID int64) ([]quoteRow, error) {
+			var list []orm.Quote
+			query := gdb.WithContext(ctx).Order("id")
+			if characterID != 0 {
+				query = query.Where("character_id = ?", characterID)
+			}
+			if sceneID != 0 {
+				query = query.Where("scene_id = ?", sceneID)
+			}
+			err := query.Find(&list).Error
+			rows := make([]quoteRow, len(list))
+			for i, q := range list {
+				rows[i] = quoteRow{id: q.ID, sceneID: q.SceneID, characterID: q.CharacterID, text: q.Text}
+			}
+			return rows, err
+		}
+	}
+
+	return ts, nil
+}
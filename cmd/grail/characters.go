@@ -0,0 +1,241 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pboyd/godbmodels/common"
+)
+
+func charactersCommand(g *globals, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("characters: missing subcommand (list, get, add, update or rm)")
+	}
+
+	subcommands := map[string]command{
+		"list":   listCharactersCommand,
+		"get":    getCharacterCommand,
+		"add":    addCharacterCommand,
+		"update": updateCharacterCommand,
+		"rm":     removeCharacterCommand,
+	}
+
+	cmd, ok := subcommands[args[0]]
+	if !ok {
+		return fmt.Errorf("characters: unknown subcommand %q", args[0])
+	}
+
+	return cmd(g, args[1:], out)
+}
+
+// withRepository opens the database and calls fn with the CharacterRepository
+// for the selected backend.
+func withRepository(g *globals, fn func(repo common.CharacterRepository) error) error {
+	db, err := openDB(g)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	repo, err := characterRepository(g, db)
+	if err != nil {
+		return err
+	}
+
+	return fn(repo)
+}
+
+func listCharactersCommand(g *globals, args []string, out io.Writer) error {
+	var (
+		filters common.CharacterFilters
+		order   string
+	)
+
+	fs := newFlagSet(g, "characters list")
+	fs.Int64Var(&filters.ActorID, "actor-id", 0, "only characters played by this actor ID")
+	fs.StringVar(&filters.ActorName, "actor", "", "only characters played by actors with a matching name")
+	fs.StringVar(&filters.Name, "name", "", "only characters with a matching name")
+	fs.Int64Var(&filters.SceneNumber, "scene", 0, "only characters that appear in this scene")
+	fs.Var((*idList)(&filters.ActorIDs), "actor-ids", "only characters played by one of these comma-separated actor `IDs`")
+	fs.Var((*idList)(&filters.ExcludeActorIDs), "exclude-actor-ids", "leave out characters played by these comma-separated actor `IDs`")
+	fs.Var((*idList)(&filters.SceneNumbers), "scenes", "only characters that appear in one of these comma-separated scene `numbers`")
+	fs.BoolVar(&filters.AllScenes, "all-scenes", false, "with --scenes, only characters that appear in every one of them")
+	fs.Int64Var(&filters.SceneFrom, "scene-from", 0, "only characters that appear in a scene numbered at least `N`")
+	fs.Int64Var(&filters.SceneTo, "scene-to", 0, "only characters that appear in a scene numbered at most `N`")
+	fs.StringVar(&order, "order", "", "sort by id, name, actor_name, scene_count or quote_count")
+	fs.BoolVar(&filters.Descending, "desc", false, "sort in descending order")
+	_, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	filters.OrderBy = common.OrderBy(order)
+
+	return withRepository(g, func(repo common.CharacterRepository) error {
+		characters, err := repo.List(context.Background(), &filters)
+		if err != nil {
+			return err
+		}
+
+		return render(g, out, characterTable(characters...))
+	})
+}
+
+// idList is a flag.Value for a comma-separated list of IDs. Repeating the
+// flag adds to the list.
+type idList []int64
+
+func (l *idList) String() string {
+	if l == nil {
+		return ""
+	}
+
+	s := make([]string, len(*l))
+	for i, id := range *l {
+		s[i] = strconv.FormatInt(id, 10)
+	}
+	return strings.Join(s, ",")
+}
+
+func (l *idList) Set(s string) error {
+	for _, field := range strings.Split(s, ",") {
+		id, err := strconv.ParseInt(strings.TrimSpace(field), 10, 64)
+		if err != nil || id <= 0 {
+			return fmt.Errorf("invalid ID %q", field)
+		}
+		*l = append(*l, id)
+	}
+	return nil
+}
+
+func getCharacterCommand(g *globals, args []string, out io.Writer) error {
+	id, err := parseID(newFlagSet(g, "characters get"), args)
+	if err != nil {
+		return err
+	}
+
+	return withRepository(g, func(repo common.CharacterRepository) error {
+		c, err := repo.Get(context.Background(), id)
+		if err != nil {
+			return err
+		}
+		if c == nil {
+			return fmt.Errorf("character %d not found", id)
+		}
+
+		return render(g, out, characterTable(c))
+	})
+}
+
+func addCharacterCommand(g *globals, args []string, out io.Writer) error {
+	var c common.Character
+
+	fs := newFlagSet(g, "characters add")
+	fs.StringVar(&c.Name, "name", "", "character name (required)")
+	fs.Int64Var(&c.ActorID, "actor-id", 0, "ID of the actor who plays the character (required)")
+	_, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if c.Name == "" || c.ActorID == 0 {
+		return errors.New("characters add: --name and --actor-id are required")
+	}
+
+	return withRepository(g, func(repo common.CharacterRepository) error {
+		err := repo.Store(context.Background(), &c)
+		if err != nil {
+			return err
+		}
+
+		return render(g, out, characterTable(&c))
+	})
+}
+
+func updateCharacterCommand(g *globals, args []string, out io.Writer) error {
+	var (
+		name    string
+		actorID int64
+	)
+
+	fs := newFlagSet(g, "characters update")
+	fs.StringVar(&name, "name", "", "new character name")
+	fs.Int64Var(&actorID, "actor-id", 0, "new actor ID")
+	id, err := parseID(fs, args)
+	if err != nil {
+		return err
+	}
+
+	return withRepository(g, func(repo common.CharacterRepository) error {
+		ctx := context.Background()
+
+		c, err := repo.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+		if c == nil {
+			return fmt.Errorf("character %d not found", id)
+		}
+
+		if name != "" {
+			c.Name = name
+		}
+		if actorID != 0 {
+			c.ActorID = actorID
+		}
+
+		err = repo.Store(ctx, c)
+		if err != nil {
+			return err
+		}
+
+		return render(g, out, characterTable(c))
+	})
+}
+
+func removeCharacterCommand(g *globals, args []string, out io.Writer) error {
+	id, err := parseID(newFlagSet(g, "characters rm"), args)
+	if err != nil {
+		return err
+	}
+
+	return withRepository(g, func(repo common.CharacterRepository) error {
+		err := repo.Delete(context.Background(), id)
+		if errors.Is(err, common.ErrNotFound) {
+			return fmt.Errorf("character %d not found", id)
+		}
+
+		return err
+	})
+}
+
+// parseID parses args with fs and returns the single positional ID argument.
+func parseID(fs *flag.FlagSet, args []string) (int64, error) {
+	positional, err := parse(fs, args)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(positional) != 1 {
+		return 0, fmt.Errorf("%s: expected one ID argument", fs.Name())
+	}
+
+	id, err := strconv.ParseInt(positional[0], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s: invalid ID %q", fs.Name(), positional[0])
+	}
+
+	return id, nil
+}
+
+func characterTable(characters ...*common.Character) *table {
+	t := &table{columns: []string{"id", "actor_id", "name"}}
+	for _, c := range characters {
+		t.add(c.ID, c.ActorID, c.Name)
+	}
+
+	return t
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pboyd/godbmodels/common"
+)
+
+func initCommand(g *globals, args []string, out io.Writer) error {
+	_, err := parse(newFlagSet(g, "init"), args)
+	if err != nil {
+		return err
+	}
+
+	db, err := openDB(g)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	fmt.Fprintf(out, "%s is up to date\n", g.dbPath)
+	return nil
+}
+
+func seedCommand(g *globals, args []string, out io.Writer) error {
+	_, err := parse(newFlagSet(g, "seed"), args)
+	if err != nil {
+		return err
+	}
+
+	db, err := openDB(g)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	err = common.Populate(db)
+	if err != nil {
+		return fmt.Errorf("seed %s: %w", g.dbPath, err)
+	}
+
+	fmt.Fprintf(out, "loaded the standard data into %s\n", g.dbPath)
+	return nil
+}
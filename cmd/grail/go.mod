@@ -0,0 +1,56 @@
+module github.com/pboyd/godbmodels/cmd/grail
+
+go 1.20
+
+require (
+	github.com/pboyd/godbmodels/builder v0.0.0
+	github.com/pboyd/godbmodels/common v0.0.0
+	github.com/pboyd/godbmodels/mapper v0.0.0
+	github.com/pboyd/godbmodels/orm v0.0.0
+	github.com/pboyd/godbmodels/sqlc v0.0.0
+	github.com/pboyd/godbmodels/vanilla v0.0.0
+	github.com/stretchr/testify v1.8.4
+)
+
+require (
+	github.com/Masterminds/squirrel v1.5.4 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/dustin/go-humanize v1.0.1 // indirect
+	github.com/google/uuid v1.3.0 // indirect
+	github.com/jinzhu/inflection v1.0.0 // indirect
+	github.com/jinzhu/now v1.1.5 // indirect
+	github.com/jmoiron/sqlx v1.3.5 // indirect
+	github.com/kballard/go-shellquote v0.0.0-20180428030007-95032a82bc51 // indirect
+	github.com/lann/builder v0.0.0-20180802200727-47ae307949d0 // indirect
+	github.com/lann/ps v0.0.0-20150810152359-62de8c46ede0 // indirect
+	github.com/mattn/go-isatty v0.0.16 // indirect
+	github.com/mattn/go-sqlite3 v1.14.17 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/remyoudompheng/bigfft v0.0.0-20230129092748-24d4a6f8daec // indirect
+	golang.org/x/mod v0.3.0 // indirect
+	golang.org/x/sys v0.0.0-20220811171246-fbc7d0a398ab // indirect
+	golang.org/x/tools v0.0.0-20201124115921-2c860bdd6e78 // indirect
+	golang.org/x/xerrors v0.0.0-20200804184101-5ec99f83aff1 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+	gorm.io/driver/sqlite v1.5.2 // indirect
+	gorm.io/gorm v1.25.2 // indirect
+	lukechampine.com/uint128 v1.2.0 // indirect
+	modernc.org/cc/v3 v3.40.0 // indirect
+	modernc.org/ccgo/v3 v3.16.13 // indirect
+	modernc.org/libc v1.24.1 // indirect
+	modernc.org/mathutil v1.5.0 // indirect
+	modernc.org/memory v1.6.0 // indirect
+	modernc.org/opt v0.1.3 // indirect
+	modernc.org/sqlite v1.25.0 // indirect
+	modernc.org/strutil v1.1.3 // indirect
+	modernc.org/token v1.0.1 // indirect
+)
+
+replace (
+	github.com/pboyd/godbmodels/builder => ../../builder
+	github.com/pboyd/godbmodels/common => ../../common
+	github.com/pboyd/godbmodels/mapper => ../../mapper
+	github.com/pboyd/godbmodels/orm => ../../orm
+	github.com/pboyd/godbmodels/sqlc => ../../sqlc
+	github.com/pboyd/godbmodels/vanilla => ../../vanilla
+)
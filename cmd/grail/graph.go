@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/pboyd/godbmodels/vanilla"
+)
+
+// The graph commands always use the vanilla package, since it's the only one
+// with a GraphStore.
+
+func graphCommand(g *globals, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("graph: missing subcommand (costars, path, dot or graphml)")
+	}
+
+	subcommands := map[string]command{
+		"costars": costarsCommand,
+		"path":    pathCommand,
+		"dot":     exportGraphCommand(func(gr *vanilla.Graph, w io.Writer) error { return gr.WriteDOT(w) }),
+		"graphml": exportGraphCommand(func(gr *vanilla.Graph, w io.Writer) error { return gr.WriteGraphML(w) }),
+	}
+
+	cmd, ok := subcommands[args[0]]
+	if !ok {
+		return fmt.Errorf("graph: unknown subcommand %q", args[0])
+	}
+
+	return cmd(g, args[1:], out)
+}
+
+func costarsCommand(g *globals, args []string, out io.Writer) error {
+	id, err := parseID(newFlagSet(g, "graph costars"), args)
+	if err != nil {
+		return err
+	}
+
+	db, err := openDB(g)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	costars, err := vanilla.NewGraphStore(db).ActorCoStars(context.Background(), id)
+	if err != nil {
+		return err
+	}
+
+	t := &table{columns: []string{"id", "name", "shared_scenes"}}
+	for _, c := range costars {
+		t.add(c.ID, c.Name, c.SharedScenes)
+	}
+
+	return render(g, out, t)
+}
+
+func pathCommand(g *globals, args []string, out io.Writer) error {
+	positional, err := parse(newFlagSet(g, "graph path"), args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 2 {
+		return errors.New("graph path: expected two actor IDs")
+	}
+
+	var ids [2]int64
+	for i, arg := range positional {
+		ids[i], err = strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			return fmt.Errorf("graph path: invalid ID %q", arg)
+		}
+	}
+
+	db, err := openDB(g)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	path, err := vanilla.NewGraphStore(db).ShortestPath(context.Background(), ids[0], ids[1])
+	if err != nil {
+		return err
+	}
+	if path == nil {
+		return fmt.Errorf("actors %d and %d are not connected", ids[0], ids[1])
+	}
+
+	t := &table{columns: []string{"id", "name", "scene"}}
+	for _, s := range path {
+		t.add(s.ID, s.Name, s.SceneID)
+	}
+
+	return render(g, out, t)
+}
+
+func exportGraphCommand(write func(*vanilla.Graph, io.Writer) error) command {
+	return func(g *globals, args []string, out io.Writer) error {
+		_, err := parse(newFlagSet(g, "graph"), args)
+		if err != nil {
+			return err
+		}
+
+		db, err := openDB(g)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		gr, err := vanilla.NewGraphStore(db).Graph(context.Background())
+		if err != nil {
+			return err
+		}
+
+		return write(gr, out)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pboyd/godbmodels/common/importer"
+)
+
+func importCommand(g *globals, args []string, out io.Writer) error {
+	var dryRun bool
+
+	fs := newFlagSet(g, "import")
+	fs.BoolVar(&dryRun, "dry-run", false, "show the changes without making them")
+	files, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(files) != 1 {
+		return errors.New("import: expected one screenplay file")
+	}
+
+	f, err := os.Open(files[0])
+	if err != nil {
+		return fmt.Errorf("import: %w", err)
+	}
+	defer f.Close()
+
+	script, err := importer.Parse(f)
+	if err != nil {
+		return fmt.Errorf("import %s: %w", files[0], err)
+	}
+
+	db, err := openDB(g)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	report, err := importer.Import(context.Background(), db, script, dryRun)
+	if errors.Is(err, importer.ErrUnmatched) {
+		t := &table{columns: []string{"line", "speaker", "candidates"}}
+		for _, u := range report.Unmatched {
+			t.add(u.Line, u.Speaker, strings.Join(u.Candidates, ", "))
+		}
+
+		rerr := render(g, out, t)
+		if rerr != nil {
+			return rerr
+		}
+
+		return fmt.Errorf("import %s: %w", files[0], err)
+	}
+	if err != nil {
+		return err
+	}
+
+	t := &table{columns: []string{"line", "action", "scene", "character", "text"}}
+	for _, c := range report.Changes {
+		t.add(c.Line, string(c.Kind), c.SceneID, c.Character, c.Text)
+	}
+
+	err = render(g, out, t)
+	if err != nil {
+		return err
+	}
+
+	// The warnings and summary go to stderr, so they're shown whatever the
+	// format without breaking the json or csv output.
+	for _, m := range report.Mismatched {
+		fmt.Fprintf(stderr, "line %d: scene %d is %q, not %q; keeping the existing name\n", m.Line, m.SceneID, m.Existing, m.Name)
+	}
+
+	verb := "imported"
+	if dryRun {
+		verb = "would import"
+	}
+	fmt.Fprintf(stderr, "%s %d quotes (%d new scenes, %d scene appearances, %d quotes already present)\n",
+		verb,
+		report.Count(importer.AddQuote),
+		report.Count(importer.CreateScene),
+		report.Count(importer.AddCharacter),
+		report.Unchanged,
+	)
+
+	return nil
+}
@@ -0,0 +1,149 @@
+// Command grail manages and queries the Holy Grail database using any of the
+// model packages.
+//
+// Usage:
+//
+//	grail [--db path] [--backend name] [--format fmt] command [args]
+//
+// The backend selects which package handles the queries: vanilla, builder,
+// mapper, sqlc or orm. Every backend handles characters, but only sqlc and orm
+// have stores for actors, scenes and quotes; vanilla has actors, builder has
+// scenes and mapper has quotes. The format is one of table, json or csv.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+const usage = `usage: grail [flags] command [args]
+
+Commands:
+  init                        create or migrate the database
+  seed                        load the standard data
+  characters list [filters]   list characters
+  characters get ID           show one character
+  characters add              add a character (--name, --actor-id)
+  characters update ID        change a character (--name, --actor-id)
+  characters rm ID            delete a character
+  actors [--name NAME]        list actors
+  scenes                      list scenes
+  quotes [filters]            list quotes
+  import [--dry-run] FILE     add the scenes and quotes from a screenplay
+  graph costars ACTOR_ID      list the actors who share a scene with an actor
+  graph path FROM TO          find the shortest chain of co-stars between actors
+  graph dot|graphml           export the co-star graph
+  schema check                compare the database to the migrations
+  schema show                 list every table and column
+
+Every backend supports the characters commands. Actors are supported by
+vanilla, sqlc and orm; scenes by builder, sqlc and orm; quotes by mapper, sqlc
+and orm.
+
+Flags:
+`
+
+func main() {
+	err := run(os.Args[1:], os.Stdout)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "grail: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// globals are the flags that are accepted before or after any command.
+type globals struct {
+	dbPath  string
+	backend string
+	format  format
+}
+
+func (g *globals) register(fs *flag.FlagSet) {
+	fs.StringVar(&g.dbPath, "db", g.dbPath, "path to the sqlite database")
+	fs.StringVar(&g.backend, "backend", g.backend, "model package to use: "+backendNames)
+	fs.Var(&g.format, "format", "output `format`: table, json or csv")
+}
+
+// stderr is where commands write messages that aren't part of their output.
+// Tests replace it.
+var stderr io.Writer = os.Stderr
+
+type command func(g *globals, args []string, out io.Writer) error
+
+var commands = map[string]command{
+	"init":       initCommand,
+	"seed":       seedCommand,
+	"characters": charactersCommand,
+	"actors":     actorsCommand,
+	"scenes":     scenesCommand,
+	"quotes":     quotesCommand,
+	"import":     importCommand,
+	"graph":      graphCommand,
+	"schema":     schemaCommand,
+}
+
+func run(args []string, out io.Writer) error {
+	g := &globals{
+		dbPath:  "grail.db",
+		backend: "vanilla",
+		format:  "table",
+	}
+
+	fs := flag.NewFlagSet("grail", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), usage)
+		fs.PrintDefaults()
+	}
+	g.register(fs)
+
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+
+	cmd, ok := commands[fs.Arg(0)]
+	if !ok {
+		fs.Usage()
+		return fmt.Errorf("unknown command %q", fs.Arg(0))
+	}
+
+	return cmd(g, fs.Args()[1:], out)
+}
+
+// newFlagSet creates a FlagSet for a command, with the global flags already
+// registered.
+func newFlagSet(g *globals, name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	g.register(fs)
+	return fs
+}
+
+// parse parses args with fs, allowing flags to appear after positional
+// arguments. The positional arguments are returned.
+func parse(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		err := fs.Parse(args)
+		if err != nil {
+			return nil, err
+		}
+
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
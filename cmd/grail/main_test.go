@@ -0,0 +1,300 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/pboyd/godbmodels/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCharacters(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "grail.db")
+	grail(t, "--db", dbPath, "seed")
+
+	for _, backend := range []string{"vanilla", "builder", "mapper", "sqlc", "orm"} {
+		t.Run(backend, func(t *testing.T) {
+			assert := assert.New(t)
+			flags := []string{"--db", dbPath, "--backend", backend, "--format", "json"}
+
+			var characters []struct {
+				ID      int64  `json:"id"`
+				ActorID int64  `json:"actor_id"`
+				Name    string `json:"name"`
+			}
+			decode(t, grail(t, append(flags, "characters", "list", "--actor-id", "3")...), &characters)
+			assert.Len(characters, 8)
+
+			decode(t, grail(t, append(flags, "characters", "list", "--scene", "3")...), &characters)
+			assert.Len(characters, 4)
+
+			decode(t, grail(t, append(flags, "characters", "list", "--actor-id", "3", "--order", "name", "--desc")...), &characters)
+			if assert.Len(characters, 8) {
+				assert.Equal(int64(14), characters[0].ID)
+			}
+
+			decode(t, grail(t, append(flags, "characters", "list", "--actor-ids", "1,3")...), &characters)
+			assert.Len(characters, 12)
+
+			decode(t, grail(t, append(flags, "characters", "list", "--actor-ids", "1", "--actor-ids", "3", "--scenes", "1,3")...), &characters)
+			assert.Len(characters, 1)
+
+			decode(t, grail(t, append(flags, "characters", "list", "--scenes", "1,3", "--all-scenes")...), &characters)
+			assert.Len(characters, 2)
+
+			decode(t, grail(t, append(flags, "characters", "list", "--scene-from", "20", "--scene-to", "23")...), &characters)
+			assert.Len(characters, 17)
+
+			decode(t, grail(t, append(flags, "characters", "list", "--scene-from", "20", "--scene-to", "23", "--exclude-actor-ids", "1")...), &characters)
+			assert.Len(characters, 16)
+
+			// Flags can come after the subcommand too.
+			decode(t, grail(t, "characters", "add", "--name", "Tim", "--actor-id", "2", "--db", dbPath, "--backend", backend, "--format", "json"), &characters)
+			if !assert.Len(characters, 1) {
+				return
+			}
+			id := characters[0].ID
+			assert.NotZero(id)
+
+			decode(t, grail(t, append(flags, "characters", "update", itoa(id), "--name", "Tim the Enchanter")...), &characters)
+			decode(t, grail(t, append(flags, "characters", "get", itoa(id))...), &characters)
+			if assert.Len(characters, 1) {
+				assert.Equal("Tim the Enchanter", characters[0].Name)
+				assert.Equal(int64(2), characters[0].ActorID)
+			}
+
+			grail(t, append(flags, "characters", "rm", itoa(id))...)
+			err := run(append(flags, "characters", "rm", itoa(id)), &bytes.Buffer{})
+			assert.EqualError(err, "character "+itoa(id)+" not found")
+		})
+	}
+}
+
+func TestFormats(t *testing.T) {
+	assert := assert.New(t)
+	dbPath := filepath.Join(t.TempDir(), "grail.db")
+	grail(t, "--db", dbPath, "seed")
+
+	out := grail(t, "--db", dbPath, "--backend", "sqlc", "--format", "csv", "scenes")
+	records, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	if assert.NoError(err) {
+		assert.Len(records, 24)
+		assert.Equal([]string{"id", "name", "characters"}, records[0])
+		assert.Equal([]string{"3", "Repression is Nine Tenths of the Law?", "4"}, records[3])
+	}
+
+	out = grail(t, "--db", dbPath, "actors", "--name", "idle")
+	assert.Equal("id  name       characters\n3   Eric Idle  8\n", out)
+
+	var quotes []map[string]interface{}
+	decode(t, grail(t, "--db", dbPath, "--backend", "sqlc", "--format", "json", "quotes", "--scene", "6"), &quotes)
+	assert.Len(quotes, 3)
+
+	err = run([]string{"--db", dbPath, "--backend", "sqlc", "--format", "xml", "scenes"}, &bytes.Buffer{})
+	assert.Error(err)
+
+	// A bad format fails before anything is written.
+	err = run([]string{"--db", dbPath, "characters", "add", "--name", "Brian", "--actor-id", "2", "--format", "xml"}, &bytes.Buffer{})
+	assert.Error(err)
+	var characters []map[string]interface{}
+	decode(t, grail(t, "--db", dbPath, "--format", "json", "characters", "list", "--name", "brian"), &characters)
+	assert.Empty(characters)
+
+	err = run([]string{"--db", dbPath, "--backend", "hibernate", "characters", "list"}, &bytes.Buffer{})
+	assert.Error(err)
+
+	err = run([]string{"--db", dbPath, "characters", "list", "--actor-ids", "1,three"}, &bytes.Buffer{})
+	assert.Error(err)
+}
+
+func TestTables(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "grail.db")
+	grail(t, "--db", dbPath, "seed")
+
+	commands := map[string]struct {
+		args     []string
+		backends []string
+	}{
+		"actors": {
+			args:     []string{"actors", "--name", "terry"},
+			backends: []string{"vanilla", "sqlc", "orm"},
+		},
+		"scenes": {
+			args:     []string{"scenes"},
+			backends: []string{"builder", "sqlc", "orm"},
+		},
+		"quotes": {
+			args:     []string{"quotes", "--character-id", "1"},
+			backends: []string{"mapper", "sqlc", "orm"},
+		},
+	}
+
+	for name, cmd := range commands {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			supported := map[string]bool{}
+			var expected string
+			for _, backend := range cmd.backends {
+				supported[backend] = true
+				out := grail(t, append([]string{"--db", dbPath, "--backend", backend, "--format", "csv"}, cmd.args...)...)
+				if expected == "" {
+					expected = out
+					continue
+				}
+				assert.Equal(expected, out, backend)
+			}
+
+			for _, backend := range []string{"vanilla", "builder", "mapper", "sqlc", "orm"} {
+				if supported[backend] {
+					continue
+				}
+				err := run(append([]string{"--db", dbPath, "--backend", backend}, cmd.args...), &bytes.Buffer{})
+				assert.EqualError(err, name+" are not supported by backend "+backend)
+			}
+		})
+	}
+}
+
+func TestSchema(t *testing.T) {
+	assert := assert.New(t)
+	dbPath := filepath.Join(t.TempDir(), "grail.db")
+	grail(t, "--db", dbPath, "init")
+
+	out := grail(t, "--db", dbPath, "schema", "check")
+	assert.Equal(dbPath+" matches the migrations\n", out)
+
+	out = grail(t, "--db", dbPath, "--format", "csv", "schema", "show")
+	assert.Contains(out, "characters,actor_id,INTEGER,true,0,actors.id\n")
+
+	db, err := sql.Open(common.DriverName, common.DSN(dbPath))
+	if !assert.NoError(err) {
+		return
+	}
+	_, err = db.Exec(`ALTER TABLE quotes ADD COLUMN mood TEXT`)
+	db.Close()
+	if !assert.NoError(err) {
+		return
+	}
+
+	var buf bytes.Buffer
+	err = run([]string{"--db", dbPath, "schema", "check"}, &buf)
+	assert.EqualError(err, dbPath+" does not match the migrations")
+	assert.Contains(buf.String(), "unexpected column quotes.mood")
+
+	err = run([]string{"--db", filepath.Join(t.TempDir(), "missing.db"), "schema", "check"}, &bytes.Buffer{})
+	assert.Error(err)
+}
+
+func TestImport(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "grail.db")
+	grail(t, "--db", dbPath, "seed")
+
+	script := filepath.Join(dir, "script.txt")
+	err := os.WriteFile(script, []byte("SCENE 24: The Black Beast of Aaaaargh\nBEDIVERE: Quickly, sire, this way!\n"), 0o644)
+	if !assert.NoError(err) {
+		return
+	}
+
+	var log bytes.Buffer
+	stderr = &log
+	defer func() { stderr = os.Stderr }()
+
+	out := grail(t, "--db", dbPath, "import", "--dry-run", script)
+	assert.Contains(out, "create scene")
+	assert.Equal("would import 1 quotes (1 new scenes, 1 scene appearances, 0 quotes already present)\n", log.String())
+
+	var changes []struct {
+		Action    string `json:"action"`
+		Scene     int64  `json:"scene"`
+		Character string `json:"character"`
+	}
+	log.Reset()
+	decode(t, grail(t, "--db", dbPath, "--format", "json", "import", script), &changes)
+	if assert.Len(changes, 3) {
+		assert.Equal("create scene", changes[0].Action)
+		assert.Equal("Sir Bedevere", changes[1].Character)
+		assert.Equal("add quote", changes[2].Action)
+	}
+	assert.Equal("imported 1 quotes (1 new scenes, 1 scene appearances, 0 quotes already present)\n", log.String())
+
+	log.Reset()
+	grail(t, "--db", dbPath, "import", script)
+	assert.Equal("imported 0 quotes (0 new scenes, 0 scene appearances, 1 quotes already present)\n", log.String())
+
+	err = os.WriteFile(script, []byte("SCENE 24: The Cave\nBEDIVERE: Quickly, sire, this way!\n"), 0o644)
+	if !assert.NoError(err) {
+		return
+	}
+	log.Reset()
+	out = grail(t, "--db", dbPath, "--format", "csv", "import", "--dry-run", script)
+	assert.Equal("line,action,scene,character,text\n", out)
+	assert.Contains(log.String(), "line 1: scene 24 is \"The Black Beast of Aaaaargh\", not \"The Cave\"; keeping the existing name\n")
+
+	err = os.WriteFile(script, []byte("SCENE 24\nBRIAN: He's not the Messiah.\n"), 0o644)
+	if !assert.NoError(err) {
+		return
+	}
+	var buf bytes.Buffer
+	err = run([]string{"--db", dbPath, "import", script}, &buf)
+	assert.Error(err)
+	assert.Contains(buf.String(), "BRIAN")
+}
+
+func TestGraph(t *testing.T) {
+	assert := assert.New(t)
+	dbPath := filepath.Join(t.TempDir(), "grail.db")
+	grail(t, "--db", dbPath, "seed")
+
+	out := grail(t, "--db", dbPath, "--format", "csv", "graph", "path", "12", "13")
+	assert.Equal("id,name,scene\n12,Rita Davies,0\n1,Graham Chapman,23\n13,Avril Stewart,10\n", out)
+
+	var costars []map[string]interface{}
+	decode(t, grail(t, "--db", dbPath, "--format", "json", "graph", "costars", "7"), &costars)
+	assert.Len(costars, 9)
+
+	out = grail(t, "--db", dbPath, "graph", "dot")
+	assert.Contains(out, "\t1 -- 2 [label=12, weight=12];\n")
+
+	out = grail(t, "--db", dbPath, "graph", "graphml")
+	assert.Contains(out, `<edge source="a1" target="a2">`)
+
+	err := run([]string{"--db", dbPath, "graph", "path", "1", "99"}, &bytes.Buffer{})
+	assert.EqualError(err, "actors 1 and 99 are not connected")
+}
+
+// grail runs the command with args and returns the output. The test fails if
+// the command returns an error.
+func grail(t *testing.T, args ...string) string {
+	t.Helper()
+
+	var out bytes.Buffer
+	err := run(args, &out)
+	if err != nil {
+		t.Fatalf("grail %s: %s", strings.Join(args, " "), err)
+	}
+
+	return out.String()
+}
+
+func decode(t *testing.T, out string, v interface{}) {
+	t.Helper()
+
+	err := json.Unmarshal([]byte(out), v)
+	if err != nil {
+		t.Fatalf("Error decoding %q: %s", out, err)
+	}
+}
+
+func itoa(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// table is the result of a command, ready to be rendered in any of the
+// output formats.
+type table struct {
+	columns []string
+	rows    [][]interface{}
+}
+
+func (t *table) add(values ...interface{}) {
+	t.rows = append(t.rows, values)
+}
+
+// format is the value of the --format flag. It's checked when the flag is
+// parsed, so a bad format fails before a command changes anything.
+type format string
+
+func (f *format) String() string {
+	return string(*f)
+}
+
+func (f *format) Set(s string) error {
+	switch s {
+	case "table", "json", "csv":
+		*f = format(s)
+		return nil
+	default:
+		return fmt.Errorf("unknown format %q, must be one of table, json or csv", s)
+	}
+}
+
+// render writes the table to out in the format named by the --format flag.
+func render(g *globals, out io.Writer, t *table) error {
+	switch g.format {
+	case "table":
+		return renderTable(out, t)
+	case "json":
+		return renderJSON(out, t)
+	case "csv":
+		return renderCSV(out, t)
+	default:
+		return fmt.Errorf("unknown format %q, must be one of table, json or csv", g.format)
+	}
+}
+
+func renderTable(out io.Writer, t *table) error {
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+
+	for i, col := range t.columns {
+		if i > 0 {
+			fmt.Fprint(tw, "\t")
+		}
+		fmt.Fprint(tw, col)
+	}
+	fmt.Fprintln(tw)
+
+	for _, row := range t.rows {
+		for i, v := range row {
+			if i > 0 {
+				fmt.Fprint(tw, "\t")
+			}
+			fmt.Fprint(tw, v)
+		}
+		fmt.Fprintln(tw)
+	}
+
+	return tw.Flush()
+}
+
+// renderJSON writes an array of objects keyed by column name.
+func renderJSON(out io.Writer, t *table) error {
+	records := make([]map[string]interface{}, len(t.rows))
+	for i, row := range t.rows {
+		record := make(map[string]interface{}, len(t.columns))
+		for j, col := range t.columns {
+			record[col] = row[j]
+		}
+		records[i] = record
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(records)
+}
+
+func renderCSV(out io.Writer, t *table) error {
+	w := csv.NewWriter(out)
+
+	err := w.Write(t.columns)
+	if err != nil {
+		return err
+	}
+
+	record := make([]string, len(t.columns))
+	for _, row := range t.rows {
+		for i, v := range row {
+			record[i] = fmt.Sprint(v)
+		}
+
+		err := w.Write(record)
+		if err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pboyd/godbmodels/common"
+	"github.com/pboyd/godbmodels/common/introspect"
+)
+
+func schemaCommand(g *globals, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("schema: missing subcommand (check or show)")
+	}
+
+	subcommands := map[string]command{
+		"check": checkSchemaCommand,
+		"show":  showSchemaCommand,
+	}
+
+	cmd, ok := subcommands[args[0]]
+	if !ok {
+		return fmt.Errorf("schema: unknown subcommand %q", args[0])
+	}
+
+	return cmd(g, args[1:], out)
+}
+
+// readSchema reads the schema of the database named by the --db flag.
+//
+// Unlike openDB, it doesn't migrate the database, since that would hide the
+// differences being looked for.
+func readSchema(g *globals) (*introspect.Schema, error) {
+	_, err := os.Stat(g.dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", g.dbPath, err)
+	}
+
+	db, err := sql.Open(common.DriverName, common.DSN(g.dbPath))
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", g.dbPath, err)
+	}
+	defer db.Close()
+
+	return introspect.Read(context.Background(), db)
+}
+
+func checkSchemaCommand(g *globals, args []string, out io.Writer) error {
+	_, err := parse(newFlagSet(g, "schema check"), args)
+	if err != nil {
+		return err
+	}
+
+	actual, err := readSchema(g)
+	if err != nil {
+		return err
+	}
+
+	expected, err := introspect.Expected(context.Background())
+	if err != nil {
+		return fmt.Errorf("load expected schema: %w", err)
+	}
+
+	diffs := introspect.Diff(expected, actual)
+	if len(diffs) == 0 {
+		fmt.Fprintf(out, "%s matches the migrations\n", g.dbPath)
+		return nil
+	}
+
+	t := &table{columns: []string{"table", "difference"}}
+	for _, d := range diffs {
+		t.add(d.Table, d.String())
+	}
+
+	err = render(g, out, t)
+	if err != nil {
+		return err
+	}
+
+	return fmt.Errorf("%s does not match the migrations", g.dbPath)
+}
+
+func showSchemaCommand(g *globals, args []string, out io.Writer) error {
+	_, err := parse(newFlagSet(g, "schema show"), args)
+	if err != nil {
+		return err
+	}
+
+	schema, err := readSchema(g)
+	if err != nil {
+		return err
+	}
+
+	t := &table{columns: []string{"table", "column", "type", "not_null", "primary_key", "references"}}
+	for _, tbl := range schema.Tables {
+		for _, c := range tbl.Columns {
+			var refs []string
+			for _, fk := range tbl.ForeignKeys {
+				for i, col := range fk.Columns {
+					if col == c.Name {
+						refs = append(refs, fk.Table+"."+fk.References[i])
+					}
+				}
+			}
+			t.add(tbl.Name, c.Name, c.Type, c.NotNull, c.PrimaryKey, strings.Join(refs, ", "))
+		}
+	}
+
+	return render(g, out, t)
+}
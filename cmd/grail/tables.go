@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"io"
+	"sort"
+
+	"github.com/pboyd/godbmodels/common"
+)
+
+// The actors, scenes and quotes commands use the --backend package's stores
+// for their table. Character counts and names come from the same backend's
+// CharacterRepository.
+
+func actorsCommand(g *globals, args []string, out io.Writer) error {
+	var name string
+
+	fs := newFlagSet(g, "actors")
+	fs.StringVar(&name, "name", "", "only actors with a matching name")
+	_, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+
+	return withTableStores(g, func(ts *tableStores) error {
+		if ts.actors == nil {
+			return ts.unsupported("actors")
+		}
+
+		ctx := context.Background()
+		actors, err := ts.actors(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		l, err := ts.characters.ListWithFacets(ctx, nil)
+		if err != nil {
+			return err
+		}
+		counts := facetCounts(l.Facets.Actors)
+
+		t := &table{columns: []string{"id", "name", "characters"}}
+		for _, a := range actors {
+			t.add(a.id, a.name, counts[a.id])
+		}
+
+		return render(g, out, t)
+	})
+}
+
+func scenesCommand(g *globals, args []string, out io.Writer) error {
+	_, err := parse(newFlagSet(g, "scenes"), args)
+	if err != nil {
+		return err
+	}
+
+	return withTableStores(g, func(ts *tableStores) error {
+		if ts.scenes == nil {
+			return ts.unsupported("scenes")
+		}
+
+		ctx := context.Background()
+		scenes, err := ts.scenes(ctx)
+		if err != nil {
+			return err
+		}
+
+		l, err := ts.characters.ListWithFacets(ctx, nil)
+		if err != nil {
+			return err
+		}
+		counts := facetCounts(l.Facets.Scenes)
+
+		t := &table{columns: []string{"id", "name", "characters"}}
+		for _, s := range scenes {
+			t.add(s.id, s.name, counts[s.id])
+		}
+
+		return render(g, out, t)
+	})
+}
+
+func quotesCommand(g *globals, args []string, out io.Writer) error {
+	var characterID, sceneID int64
+
+	fs := newFlagSet(g, "quotes")
+	fs.Int64Var(&characterID, "character-id", 0, "only quotes by this character ID")
+	fs.Int64Var(&sceneID, "scene", 0, "only quotes from this scene")
+	_, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+
+	return withTableStores(g, func(ts *tableStores) error {
+		if ts.quotes == nil {
+			return ts.unsupported("quotes")
+		}
+
+		ctx := context.Background()
+		quotes, err := ts.quotes(ctx, characterID, sceneID)
+		if err != nil {
+			return err
+		}
+		sort.SliceStable(quotes, func(i, j int) bool {
+			return quotes[i].sceneID < quotes[j].sceneID
+		})
+
+		characters, err := ts.characters.List(ctx, nil)
+		if err != nil {
+			return err
+		}
+		names := make(map[int64]string, len(characters))
+		for _, c := range characters {
+			names[c.ID] = c.Name
+		}
+
+		t := &table{columns: []string{"id", "scene_id", "character", "text"}}
+		for _, q := range quotes {
+			t.add(q.id, q.sceneID, names[q.characterID], q.text)
+		}
+
+		return render(g, out, t)
+	})
+}
+
+// withTableStores opens the database and calls fn with the backend's stores.
+func withTableStores(g *globals, fn func(ts *tableStores) error) error {
+	db, err := openDB(g)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ts, err := newTableStores(g, db)
+	if err != nil {
+		return err
+	}
+
+	return fn(ts)
+}
+
+// facetCounts converts facet counts to a map.
+func facetCounts(facets []common.FacetCount) map[int64]int {
+	counts := make(map[int64]int, len(facets))
+	for _, f := range facets {
+		counts[f.ID] = f.Count
+	}
+	return counts
+}
@@ -1,31 +1,40 @@
 package common
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
+	"strings"
 	"testing"
 
 	_ "embed"
-
-	_ "github.com/mattn/go-sqlite3"
 )
 
-//go:embed schema.sql
-var schema string
-
 //go:embed grail.sql
 var standardData string
 
-// Open connects to a sqlite database and loads the schema. If the database
-// file does not exist, it will be created.
+// DSN returns the data source name to open the database at dbPath with
+// DriverName. It turns on foreign keys, which must be set on every
+// connection.
+func DSN(dbPath string) string {
+	if strings.Contains(dbPath, "?") {
+		return dbPath + "&" + dsnParams
+	}
+	return dbPath + "?" + dsnParams
+}
+
+// Open connects to a sqlite database and migrates the schema to the latest
+// version. If the database file does not exist, it will be created.
 func Open(dbPath string) (*sql.DB, error) {
-	db, err := sql.Open("sqlite3", dbPath)
+	db, err := sql.Open(DriverName, DSN(dbPath))
 	if err != nil {
 		return nil, err
 	}
 
 	// Load the schema
-	_, err = db.Exec(schema)
+	err = Migrate(context.Background(), db, Latest)
 	if err != nil {
+		db.Close()
 		return nil, err
 	}
 
@@ -39,27 +48,64 @@ func Populate(db *sql.DB) error {
 }
 
 // TestDB creates a new in-memory database for testing. The schema is loaded
-// and some test data is populated. If there is an error, the test is aborted
-// (t.Fatal).
-func TestDB(t *testing.T) *sql.DB {
-	db, err := sql.Open("sqlite3", ":memory:")
+// and some test data is populated. The database is closed when the test
+// finishes. If there is an error, the test is aborted (t.Fatal).
+//
+// TestTx is faster when the test doesn't need a database of its own.
+func TestDB(t testing.TB) *sql.DB {
+	db, err := openTestDB(true)
+	if err != nil {
+		t.Fatalf("Error opening database: %s", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+// TestEmptyDB is like TestDB, but the database only has the schema, not the
+// test data. Use it with the fixtures package to build exactly the data a
+// test needs.
+func TestEmptyDB(t testing.TB) *sql.DB {
+	db, err := openTestDB(false)
 	if err != nil {
 		t.Fatalf("Error opening database: %s", err)
 	}
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+// openTestDB creates an in-memory database with the schema and, if populate
+// is true, the test data.
+func openTestDB(populate bool) (*sql.DB, error) {
+	db, err := sql.Open(DriverName, DSN(":memory:"))
+	if err != nil {
+		return nil, err
+	}
+
+	// Every connection to :memory: gets its own empty database, so the pool
+	// must never open a second one.
+	db.SetMaxOpenConns(1)
+	db.SetConnMaxLifetime(0)
+	db.SetConnMaxIdleTime(0)
 
 	// Load the schema
-	_, err = db.Exec(schema)
+	err = Migrate(context.Background(), db, Latest)
 	if err != nil {
 		db.Close()
-		t.Fatalf("Error loading schema: %s", err)
+		return nil, fmt.Errorf("load schema: %w", err)
+	}
+
+	if !populate {
+		return db, nil
 	}
 
 	// Load the test data
 	err = Populate(db)
 	if err != nil {
 		db.Close()
-		t.Fatalf("Error loading test data: %s", err)
+		return nil, fmt.Errorf("load test data: %w", err)
 	}
 
-	return db
+	return db, nil
 }
@@ -0,0 +1,25 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForeignKeys(t *testing.T) {
+	assert := assert.New(t)
+	tx := TestTx(t)
+
+	var enabled bool
+	err := tx.QueryRow(`PRAGMA foreign_keys`).Scan(&enabled)
+	if assert.NoError(err) {
+		assert.True(enabled)
+	}
+
+	_, err = tx.Exec(`INSERT INTO characters (actor_id, name) VALUES (9999, 'Sir Not-Appearing-in-this-Film')`)
+	assert.Error(err)
+
+	// A character can't be deleted while it has scenes or quotes.
+	_, err = tx.Exec(`DELETE FROM characters WHERE id = (SELECT character_id FROM quotes LIMIT 1)`)
+	assert.Error(err)
+}
@@ -0,0 +1,265 @@
+package common
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+)
+
+// PlaceholderStyle is how a database numbers bind parameters.
+type PlaceholderStyle int
+
+const (
+	// QuestionPlaceholders uses "?" for every parameter.
+	QuestionPlaceholders PlaceholderStyle = iota
+
+	// DollarPlaceholders numbers parameters "$1", "$2", and so on.
+	DollarPlaceholders
+)
+
+// IDStrategy is how a database reports the ID of an inserted row.
+type IDStrategy int
+
+const (
+	// ReturningID appends "RETURNING id" to the INSERT and reads the row.
+	ReturningID IDStrategy = iota
+
+	// LastInsertID runs the INSERT as a statement and calls
+	// sql.Result.LastInsertId.
+	LastInsertID
+)
+
+// IDListStyle is how a database unpacks a JSON array of IDs in a query.
+type IDListStyle int
+
+const (
+	// JSONEach selects from SQLite's json_each function.
+	JSONEach IDListStyle = iota
+
+	// JSONBArrayElements selects from PostgreSQL's
+	// jsonb_array_elements_text function.
+	JSONBArrayElements
+
+	// JSONTable selects from MySQL's JSON_TABLE function.
+	JSONTable
+)
+
+// Dialect describes the SQL syntax that differs between databases.
+//
+// The stores write their queries with "?" placeholders and double-quoted
+// identifiers, and the Dialect rewrites them with Rebind before they're run.
+type Dialect struct {
+	// Name identifies the dialect.
+	Name string
+
+	// Placeholders is the bind parameter style.
+	Placeholders PlaceholderStyle
+
+	// IdentifierQuote is the character that quotes identifiers.
+	IdentifierQuote byte
+
+	// InsertID is how the ID of a new row is found.
+	InsertID IDStrategy
+
+	// ILike is true if the database has an ILIKE operator for
+	// case-insensitive matches. Otherwise both sides are passed to LOWER.
+	ILike bool
+
+	// IDList is how InIDs unpacks a list of IDs.
+	IDList IDListStyle
+}
+
+var (
+	// SQLite is the dialect for SQLite, which is all this repository
+	// actually runs against.
+	SQLite = &Dialect{
+		Name:            "sqlite",
+		Placeholders:    QuestionPlaceholders,
+		IdentifierQuote: '"',
+		InsertID:        ReturningID,
+		IDList:          JSONEach,
+	}
+
+	// Postgres is the dialect for PostgreSQL.
+	Postgres = &Dialect{
+		Name:            "postgres",
+		Placeholders:    DollarPlaceholders,
+		IdentifierQuote: '"',
+		InsertID:        ReturningID,
+		ILike:           true,
+		IDList:          JSONBArrayElements,
+	}
+
+	// MySQL is the dialect for MySQL.
+	MySQL = &Dialect{
+		Name:            "mysql",
+		Placeholders:    QuestionPlaceholders,
+		IdentifierQuote: '`',
+		InsertID:        LastInsertID,
+		IDList:          JSONTable,
+	}
+)
+
+// Dialects lists every dialect.
+var Dialects = []*Dialect{SQLite, Postgres, MySQL}
+
+// Rebind rewrites a query that uses "?" placeholders and double-quoted
+// identifiers into the dialect's syntax. String literals are left alone.
+func (d *Dialect) Rebind(query string) string {
+	if d.Placeholders == QuestionPlaceholders && d.IdentifierQuote == '"' {
+		return query
+	}
+
+	var b strings.Builder
+	b.Grow(len(query) + 8)
+
+	n := 0
+	inString := false
+	for i := 0; i < len(query); i++ {
+		ch := query[i]
+		switch {
+		case ch == '\'':
+			inString = !inString
+		case inString:
+		case ch == '"':
+			ch = d.IdentifierQuote
+		case ch == '?' && d.Placeholders == DollarPlaceholders:
+			n++
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(n))
+			continue
+		}
+		b.WriteByte(ch)
+	}
+
+	return b.String()
+}
+
+// ReplacePlaceholders is the same as Rebind. It lets a Dialect be used as a
+// squirrel.PlaceholderFormat.
+func (d *Dialect) ReplacePlaceholders(query string) (string, error) {
+	return d.Rebind(query), nil
+}
+
+// ContainsFold returns a condition, with one "?" placeholder, that does a
+// case-insensitive partial match of s on expr, along with the argument for
+// the placeholder.
+func (d *Dialect) ContainsFold(expr, s string) (string, interface{}) {
+	pattern := "%" + strings.ToLower(s) + "%"
+	if d.ILike {
+		return expr + " ILIKE ?", pattern
+	}
+	return "LOWER(" + expr + ") LIKE ?", pattern
+}
+
+// InIDs returns a condition, with one "?" placeholder, that matches when expr
+// is one of ids, along with the argument for the placeholder. The IDs are
+// passed as a JSON array (see JSONIDs), so there can be any number of them.
+func (d *Dialect) InIDs(expr string, ids []int64) (string, interface{}) {
+	arg := JSONIDs(ids)
+	switch d.IDList {
+	case JSONBArrayElements:
+		return expr + " IN (SELECT jsonb_array_elements_text(?::jsonb)::bigint)", arg
+	case JSONTable:
+		return expr + " IN (SELECT ids.id FROM JSON_TABLE(?, '$[*]' COLUMNS (id BIGINT PATH '$')) AS ids)", arg
+	}
+	return expr + " IN (SELECT value FROM json_each(?))", arg
+}
+
+// CharacterFilterClauses returns the joins, WHERE conditions and arguments
+// that apply filters to a query on characters c. The conditions use "?"
+// placeholders, so the query still needs to go through Rebind.
+func (d *Dialect) CharacterFilterClauses(filters *CharacterFilters) (joins, where []string, args []interface{}) {
+	if filters == nil {
+		return
+	}
+
+	if filters.ActorID != 0 {
+		where = append(where, "c.actor_id = ?")
+		args = append(args, filters.ActorID)
+	} else if filters.ActorName != "" {
+		joins = append(joins, "JOIN actors a ON a.id = c.actor_id")
+		cond, arg := d.ContainsFold("a.name", filters.ActorName)
+		where = append(where, cond)
+		args = append(args, arg)
+	}
+
+	if filters.Name != "" {
+		cond, arg := d.ContainsFold("c.name", filters.Name)
+		where = append(where, cond)
+		args = append(args, arg)
+	}
+
+	if filters.SceneNumber != 0 {
+		joins = append(joins, "JOIN scene_characters sc ON sc.character_id = c.id")
+		where = append(where, "sc.scene_id = ?")
+		args = append(args, filters.SceneNumber)
+	}
+
+	if len(filters.ActorIDs) > 0 {
+		cond, arg := d.InIDs("c.actor_id", filters.ActorIDs)
+		where = append(where, cond)
+		args = append(args, arg)
+	}
+
+	if len(filters.ExcludeActorIDs) > 0 {
+		cond, arg := d.InIDs("c.actor_id", filters.ExcludeActorIDs)
+		where = append(where, "NOT "+cond)
+		args = append(args, arg)
+	}
+
+	// The scene conditions find the matching character IDs first, instead of
+	// checking each character in turn, so the list of scenes is only read
+	// once.
+	if len(filters.SceneNumbers) > 0 {
+		cond, arg := d.InIDs("ssc.scene_id", filters.SceneNumbers)
+		query := "c.id IN (SELECT ssc.character_id FROM scene_characters ssc WHERE " + cond
+		args = append(args, arg)
+		if filters.AllScenes {
+			// The character has to be in as many of the scenes as there are
+			// scenes in the list.
+			query += " GROUP BY ssc.character_id HAVING COUNT(*) = ?"
+			args = append(args, len(DistinctIDs(filters.SceneNumbers)))
+		}
+		where = append(where, query+")")
+	}
+
+	if filters.SceneFrom != 0 || filters.SceneTo != 0 {
+		var cond []string
+		if filters.SceneFrom != 0 {
+			cond = append(cond, "rsc.scene_id >= ?")
+			args = append(args, filters.SceneFrom)
+		}
+		if filters.SceneTo != 0 {
+			cond = append(cond, "rsc.scene_id <= ?")
+			args = append(args, filters.SceneTo)
+		}
+		where = append(where, "c.id IN (SELECT rsc.character_id FROM scene_characters rsc WHERE "+strings.Join(cond, " AND ")+")")
+	}
+
+	return
+}
+
+// Inserter is implemented by *sql.DB, *sql.Tx and *sql.Conn.
+type Inserter interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// Insert runs an INSERT query, which must use "?" placeholders, and returns
+// the ID of the new row. The query is rebound, and the ID is read with the
+// dialect's IDStrategy.
+func (d *Dialect) Insert(ctx context.Context, db Inserter, query string, args ...interface{}) (int64, error) {
+	if d.InsertID == LastInsertID {
+		res, err := db.ExecContext(ctx, d.Rebind(query), args...)
+		if err != nil {
+			return 0, err
+		}
+		return res.LastInsertId()
+	}
+
+	var id int64
+	err := db.QueryRowContext(ctx, d.Rebind(query+` RETURNING "id"`), args...).Scan(&id)
+	return id, err
+}
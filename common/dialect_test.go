@@ -0,0 +1,109 @@
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRebind(t *testing.T) {
+	const query = `SELECT c.id AS "character.id" FROM characters c WHERE c.id = ? AND c.name <> 'Who? "Me"?' AND c.actor_id IN (?, ?)`
+
+	cases := map[string]string{
+		"sqlite":   query,
+		"postgres": `SELECT c.id AS "character.id" FROM characters c WHERE c.id = $1 AND c.name <> 'Who? "Me"?' AND c.actor_id IN ($2, $3)`,
+		"mysql":    "SELECT c.id AS `character.id` FROM characters c WHERE c.id = ? AND c.name <> 'Who? \"Me\"?' AND c.actor_id IN (?, ?)",
+	}
+
+	for _, d := range Dialects {
+		assert.Equal(t, cases[d.Name], d.Rebind(query), d.Name)
+	}
+}
+
+func TestContainsFold(t *testing.T) {
+	assert := assert.New(t)
+
+	cond, arg := SQLite.ContainsFold("a.name", "Eric")
+	assert.Equal("LOWER(a.name) LIKE ?", cond)
+	assert.Equal("%eric%", arg)
+
+	cond, arg = Postgres.ContainsFold("a.name", "Eric")
+	assert.Equal("a.name ILIKE ?", cond)
+	assert.Equal("%eric%", arg)
+}
+
+func TestInIDs(t *testing.T) {
+	assert := assert.New(t)
+
+	cond, arg := SQLite.InIDs("c.actor_id", []int64{1, 2})
+	assert.Equal("c.actor_id IN (SELECT value FROM json_each(?))", cond)
+	assert.Equal("[1,2]", arg)
+
+	cond, _ = Postgres.InIDs("c.actor_id", []int64{1, 2})
+	assert.Equal("c.actor_id IN (SELECT jsonb_array_elements_text(?::jsonb)::bigint)", cond)
+
+	cond, _ = MySQL.InIDs("c.actor_id", []int64{1, 2})
+	assert.Equal("c.actor_id IN (SELECT ids.id FROM JSON_TABLE(?, '$[*]' COLUMNS (id BIGINT PATH '$')) AS ids)", cond)
+}
+
+func TestCharacterFilterClauses(t *testing.T) {
+	assert := assert.New(t)
+
+	joins, where, args := SQLite.CharacterFilterClauses(nil)
+	assert.Empty(joins)
+	assert.Empty(where)
+	assert.Empty(args)
+
+	joins, where, args = SQLite.CharacterFilterClauses(&CharacterFilters{
+		ActorName:    "Palin",
+		SceneNumbers: []int64{1, 3, 3},
+		AllScenes:    true,
+		SceneTo:      15,
+	})
+	assert.Equal([]string{"JOIN actors a ON a.id = c.actor_id"}, joins)
+	assert.Equal([]string{
+		"LOWER(a.name) LIKE ?",
+		"c.id IN (SELECT ssc.character_id FROM scene_characters ssc WHERE ssc.scene_id IN (SELECT value FROM json_each(?)) GROUP BY ssc.character_id HAVING COUNT(*) = ?)",
+		"c.id IN (SELECT rsc.character_id FROM scene_characters rsc WHERE rsc.scene_id <= ?)",
+	}, where)
+	assert.Equal([]interface{}{"%palin%", "[1,3,3]", 2, int64(15)}, args)
+}
+
+func TestDialectSQLite(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	tx := TestTx(t)
+
+	id, err := SQLite.Insert(ctx, tx, `INSERT INTO actors (name) VALUES (?)`, "Zoot's Understudy")
+	if !assert.NoError(err) {
+		return
+	}
+	assert.NotZero(id)
+
+	cond, arg := SQLite.ContainsFold("name", "ZOOT'S under")
+	var found int64
+	err = tx.QueryRowContext(ctx, SQLite.Rebind(`SELECT id FROM actors WHERE `+cond), arg).Scan(&found)
+	if assert.NoError(err) {
+		assert.Equal(id, found)
+	}
+
+	// More IDs than SQLite allows parameters.
+	ids := make([]int64, 40000)
+	for i := range ids {
+		ids[i] = id + int64(i)
+	}
+	cond, arg = SQLite.InIDs("id", ids)
+	err = tx.QueryRowContext(ctx, `SELECT id FROM actors WHERE `+cond, arg).Scan(&found)
+	if assert.NoError(err) {
+		assert.Equal(id, found)
+	}
+
+	// SQLite can use either strategy.
+	mysqlish := *SQLite
+	mysqlish.InsertID = LastInsertID
+	id2, err := mysqlish.Insert(ctx, tx, `INSERT INTO actors (name) VALUES (?)`, "Dingo's Understudy")
+	if assert.NoError(err) {
+		assert.Equal(id+1, id2)
+	}
+}
@@ -0,0 +1,17 @@
+//go:build !purego
+
+package common
+
+import (
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// DriverName is the database/sql driver used by Open and the test helpers.
+//
+// By default it's github.com/mattn/go-sqlite3, which requires cgo. Build with
+// the purego tag to use modernc.org/sqlite instead.
+const DriverName = "sqlite3"
+
+// dsnParams are added to every DSN to turn on foreign key enforcement, which
+// SQLite leaves off by default.
+const dsnParams = "_foreign_keys=1"
@@ -0,0 +1,16 @@
+//go:build purego
+
+package common
+
+import (
+	_ "modernc.org/sqlite"
+)
+
+// DriverName is the database/sql driver used by Open and the test helpers.
+//
+// The purego build tag selects modernc.org/sqlite, which doesn't need cgo.
+const DriverName = "sqlite"
+
+// dsnParams are added to every DSN to turn on foreign key enforcement, which
+// SQLite leaves off by default.
+const dsnParams = "_pragma=foreign_keys(1)"
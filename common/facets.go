@@ -0,0 +1,27 @@
+package common
+
+// FacetCount is the number of characters that share a value, such as an
+// actor or a scene.
+type FacetCount struct {
+	ID    int64
+	Count int
+}
+
+// Facets counts the characters that match a set of filters, grouped a few
+// different ways.
+type Facets struct {
+	// Actors has the number of matching characters played by each actor, in
+	// order of actor ID.
+	Actors []FacetCount
+
+	// Scenes has the number of matching characters in each scene, in order of
+	// scene ID. Scenes without any matching characters are left out.
+	Scenes []FacetCount
+}
+
+// CharacterList is a list of characters with facets for the filters that
+// found them.
+type CharacterList struct {
+	Characters []*Character
+	Facets     Facets
+}
@@ -0,0 +1,340 @@
+// Package fixtures builds actors, characters, scenes and quotes for tests.
+//
+// Each factory is configured with chained methods and then inserted with
+// Create, which also inserts anything the new row depends on:
+//
+//	idle := fixtures.NewActor().Named("Eric Idle").Create(t, db)
+//	scene := fixtures.NewScene().Create(t, db)
+//	robin := fixtures.NewCharacter().Named("Sir Robin").PlayedBy(idle).InScenes(scene).Create(t, db)
+//	fixtures.NewQuote().By(robin).In(scene).Create(t, db)
+//
+// Fields that aren't set get unique placeholder values, so factories can be
+// used in a database that already has data.
+package fixtures
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync/atomic"
+	"testing"
+)
+
+// DBTX is implemented by both *sql.DB and *sql.Tx.
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// Actor is an actor row created by an ActorFactory.
+type Actor struct {
+	ID   int64
+	Name string
+}
+
+// Character is a character row created by a CharacterFactory.
+type Character struct {
+	ID      int64
+	ActorID int64
+	Name    string
+
+	// SceneIDs are the scenes the character was added to.
+	SceneIDs []int64
+}
+
+// Scene is a scene row created by a SceneFactory.
+type Scene struct {
+	ID   int64
+	Name string
+}
+
+// Quote is a quote row created by a QuoteFactory.
+type Quote struct {
+	ID          int64
+	CharacterID int64
+	SceneID     int64
+	Text        string
+}
+
+var sequence atomic.Int64
+
+// placeholder returns a unique name for a row that wasn't given one.
+func placeholder(kind string) string {
+	return fmt.Sprintf("%s %d", kind, sequence.Add(1))
+}
+
+// must aborts the test if err is not nil.
+func must(t testing.TB, kind string, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("Error creating %s fixture: %s", kind, err)
+	}
+}
+
+// ActorFactory creates actors.
+type ActorFactory struct {
+	name string
+}
+
+// NewActor returns a factory for an actor.
+func NewActor() *ActorFactory {
+	return &ActorFactory{}
+}
+
+// Named sets the actor's name.
+func (f *ActorFactory) Named(name string) *ActorFactory {
+	f.name = name
+	return f
+}
+
+// Insert adds the actor to the database.
+func (f *ActorFactory) Insert(ctx context.Context, db DBTX) (*Actor, error) {
+	a := &Actor{Name: f.name}
+	if a.Name == "" {
+		a.Name = placeholder("Actor")
+	}
+
+	err := db.QueryRowContext(ctx, `INSERT INTO actors (name) VALUES (?) RETURNING id`, a.Name).Scan(&a.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// Create is like Insert, but aborts the test on error.
+func (f *ActorFactory) Create(t testing.TB, db DBTX) *Actor {
+	t.Helper()
+
+	a, err := f.Insert(context.Background(), db)
+	must(t, "actor", err)
+	return a
+}
+
+// SceneFactory creates scenes.
+type SceneFactory struct {
+	id   int64
+	name string
+}
+
+// NewScene returns a factory for a scene.
+func NewScene() *SceneFactory {
+	return &SceneFactory{}
+}
+
+// Numbered sets the scene number. If it isn't set, the next unused number is
+// used.
+func (f *SceneFactory) Numbered(id int64) *SceneFactory {
+	f.id = id
+	return f
+}
+
+// Named sets the scene's name.
+func (f *SceneFactory) Named(name string) *SceneFactory {
+	f.name = name
+	return f
+}
+
+// Insert adds the scene to the database.
+func (f *SceneFactory) Insert(ctx context.Context, db DBTX) (*Scene, error) {
+	s := &Scene{Name: f.name}
+	if s.Name == "" {
+		s.Name = placeholder("Scene")
+	}
+
+	var id interface{}
+	if f.id != 0 {
+		id = f.id
+	}
+
+	err := db.QueryRowContext(ctx, `INSERT INTO scenes (id, name) VALUES (?, ?) RETURNING id`, id, s.Name).Scan(&s.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Create is like Insert, but aborts the test on error.
+func (f *SceneFactory) Create(t testing.TB, db DBTX) *Scene {
+	t.Helper()
+
+	s, err := f.Insert(context.Background(), db)
+	must(t, "scene", err)
+	return s
+}
+
+// CharacterFactory creates characters.
+type CharacterFactory struct {
+	name   string
+	actor  *Actor
+	scenes []*Scene
+}
+
+// NewCharacter returns a factory for a character.
+func NewCharacter() *CharacterFactory {
+	return &CharacterFactory{}
+}
+
+// Named sets the character's name.
+func (f *CharacterFactory) Named(name string) *CharacterFactory {
+	f.name = name
+	return f
+}
+
+// PlayedBy sets the actor who plays the character. If it isn't set, a new
+// actor is created.
+func (f *CharacterFactory) PlayedBy(a *Actor) *CharacterFactory {
+	f.actor = a
+	return f
+}
+
+// InScenes adds the character to scenes.
+func (f *CharacterFactory) InScenes(scenes ...*Scene) *CharacterFactory {
+	f.scenes = append(f.scenes, scenes...)
+	return f
+}
+
+// Insert adds the character, and its scene appearances, to the database.
+func (f *CharacterFactory) Insert(ctx context.Context, db DBTX) (*Character, error) {
+	actor := f.actor
+	if actor == nil {
+		var err error
+		actor, err = NewActor().Insert(ctx, db)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	c := &Character{
+		ActorID: actor.ID,
+		Name:    f.name,
+	}
+	if c.Name == "" {
+		c.Name = placeholder("Character")
+	}
+
+	err := db.QueryRowContext(ctx, `INSERT INTO characters (actor_id, name) VALUES (?, ?) RETURNING id`, c.ActorID, c.Name).Scan(&c.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range f.scenes {
+		err := addToScene(ctx, db, c, s.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// Create is like Insert, but aborts the test on error.
+func (f *CharacterFactory) Create(t testing.TB, db DBTX) *Character {
+	t.Helper()
+
+	c, err := f.Insert(context.Background(), db)
+	must(t, "character", err)
+	return c
+}
+
+func addToScene(ctx context.Context, db DBTX, c *Character, sceneID int64) error {
+	for _, id := range c.SceneIDs {
+		if id == sceneID {
+			return nil
+		}
+	}
+
+	_, err := db.ExecContext(ctx, `INSERT OR IGNORE INTO scene_characters (scene_id, character_id) VALUES (?, ?)`, sceneID, c.ID)
+	if err != nil {
+		return err
+	}
+
+	c.SceneIDs = append(c.SceneIDs, sceneID)
+	return nil
+}
+
+// QuoteFactory creates quotes.
+type QuoteFactory struct {
+	text      string
+	character *Character
+	scene     *Scene
+}
+
+// NewQuote returns a factory for a quote.
+func NewQuote() *QuoteFactory {
+	return &QuoteFactory{}
+}
+
+// Saying sets the text of the quote.
+func (f *QuoteFactory) Saying(text string) *QuoteFactory {
+	f.text = text
+	return f
+}
+
+// By sets the character who says the quote. If it isn't set, a new
+// character is created.
+func (f *QuoteFactory) By(c *Character) *QuoteFactory {
+	f.character = c
+	return f
+}
+
+// In sets the scene the quote is from. If it isn't set, a new scene is
+// created.
+func (f *QuoteFactory) In(s *Scene) *QuoteFactory {
+	f.scene = s
+	return f
+}
+
+// Insert adds the quote to the database. The character is added to the scene
+// if they aren't in it already.
+func (f *QuoteFactory) Insert(ctx context.Context, db DBTX) (*Quote, error) {
+	scene := f.scene
+	if scene == nil {
+		var err error
+		scene, err = NewScene().Insert(ctx, db)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	character := f.character
+	if character == nil {
+		var err error
+		character, err = NewCharacter().Insert(ctx, db)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := addToScene(ctx, db, character, scene.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &Quote{
+		CharacterID: character.ID,
+		SceneID:     scene.ID,
+		Text:        f.text,
+	}
+	if q.Text == "" {
+		q.Text = placeholder("Quote")
+	}
+
+	err = db.QueryRowContext(ctx, `INSERT INTO quotes (character_id, scene_id, text) VALUES (?, ?, ?) RETURNING id`, q.CharacterID, q.SceneID, q.Text).Scan(&q.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return q, nil
+}
+
+// Create is like Insert, but aborts the test on error.
+func (f *QuoteFactory) Create(t testing.TB, db DBTX) *Quote {
+	t.Helper()
+
+	q, err := f.Insert(context.Background(), db)
+	must(t, "quote", err)
+	return q
+}
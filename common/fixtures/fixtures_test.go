@@ -0,0 +1,79 @@
+package fixtures_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/pboyd/godbmodels/common"
+	"github.com/pboyd/godbmodels/common/fixtures"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFixtures(t *testing.T) {
+	assert := assert.New(t)
+	db := common.TestEmptyDB(t)
+
+	idle := fixtures.NewActor().Named("Eric Idle").Create(t, db)
+	assert.NotZero(idle.ID)
+	assert.Equal("Eric Idle", idle.Name)
+
+	bridge := fixtures.NewScene().Numbered(22).Named("The Bridge Keeper Doesn't Swallow").Create(t, db)
+	assert.Equal(int64(22), bridge.ID)
+	rabbit := fixtures.NewScene().Create(t, db)
+	assert.Equal(int64(23), rabbit.ID)
+	assert.NotEmpty(rabbit.Name)
+
+	robin := fixtures.NewCharacter().
+		Named("Sir Robin").
+		PlayedBy(idle).
+		InScenes(bridge, rabbit).
+		Create(t, db)
+	assert.Equal(idle.ID, robin.ActorID)
+	assert.Equal([]int64{22, 23}, robin.SceneIDs)
+
+	// A character without an actor gets a new one.
+	maynard := fixtures.NewCharacter().Named("Brother Maynard").Create(t, db)
+	assert.NotEqual(idle.ID, maynard.ActorID)
+	assert.Empty(maynard.SceneIDs)
+
+	// A quote puts its character in the scene.
+	q := fixtures.NewQuote().Saying("Three shall be the number thou shalt count").By(maynard).In(rabbit).Create(t, db)
+	assert.Equal(maynard.ID, q.CharacterID)
+	assert.Equal(rabbit.ID, q.SceneID)
+	assert.Equal([]int64{23}, maynard.SceneIDs)
+
+	// A quote with nothing set creates everything.
+	q = fixtures.NewQuote().Create(t, db)
+	assert.NotZero(q.CharacterID)
+	assert.NotZero(q.SceneID)
+
+	assert.Equal(3, count(t, db, `SELECT COUNT(*) FROM actors`))
+	assert.Equal(3, count(t, db, `SELECT COUNT(*) FROM characters`))
+	assert.Equal(3, count(t, db, `SELECT COUNT(*) FROM scenes`))
+	assert.Equal(2, count(t, db, `SELECT COUNT(*) FROM quotes`))
+	assert.Equal(2, count(t, db, `SELECT COUNT(*) FROM scene_characters WHERE scene_id = 23`))
+}
+
+func TestFixturesWithStandardData(t *testing.T) {
+	tx := common.TestTx(t)
+
+	// Fixtures don't collide with existing rows.
+	s := fixtures.NewScene().Create(t, tx)
+	assert.Equal(t, int64(24), s.ID)
+
+	c := fixtures.NewCharacter().InScenes(s).Create(t, tx)
+	assert.Equal(t, int64(82), c.ID)
+	assert.Equal(t, int64(36), c.ActorID)
+}
+
+func count(t *testing.T, db *sql.DB, query string) int {
+	t.Helper()
+
+	var n int
+	err := db.QueryRow(query).Scan(&n)
+	if err != nil {
+		t.Fatalf("Error counting: %s", err)
+	}
+
+	return n
+}
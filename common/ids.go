@@ -0,0 +1,42 @@
+package common
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DistinctIDs returns the IDs in ids without duplicates, in ascending order.
+func DistinctIDs(ids []int64) []int64 {
+	distinct := make([]int64, 0, len(ids))
+	seen := make(map[int64]struct{}, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		distinct = append(distinct, id)
+	}
+
+	sort.Slice(distinct, func(i, j int) bool { return distinct[i] < distinct[j] })
+	return distinct
+}
+
+// JSONIDs encodes ids as a JSON array.
+//
+// A list of IDs is passed to a query as one JSON parameter, instead of one
+// parameter per ID, because SQLite limits the number of parameters in a
+// query. The limit is 32766 by default, and only 999 before SQLite 3.32.
+func JSONIDs(ids []int64) string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, id := range ids {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.FormatInt(id, 10))
+	}
+	b.WriteByte(']')
+
+	return b.String()
+}
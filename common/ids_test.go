@@ -0,0 +1,22 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistinctIDs(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal([]int64{1, 3, 5}, DistinctIDs([]int64{5, 1, 3, 1, 5}))
+	assert.Empty(DistinctIDs(nil))
+}
+
+func TestJSONIDs(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("[]", JSONIDs(nil))
+	assert.Equal("[3]", JSONIDs([]int64{3}))
+	assert.Equal("[1,-2,30]", JSONIDs([]int64{1, -2, 30}))
+}
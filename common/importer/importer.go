@@ -0,0 +1,378 @@
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnmatched is returned by Import when a speaker doesn't match exactly one
+// character. The report lists the speakers in Unmatched.
+var ErrUnmatched = errors.New("some speakers don't match a character")
+
+// ChangeKind is the kind of change Import makes.
+type ChangeKind string
+
+const (
+	// CreateScene adds a scene.
+	CreateScene ChangeKind = "create scene"
+
+	// AddCharacter adds a character to a scene.
+	AddCharacter ChangeKind = "add to scene"
+
+	// AddQuote adds a quote.
+	AddQuote ChangeKind = "add quote"
+)
+
+// Change is one change Import makes to the database.
+type Change struct {
+	Kind ChangeKind
+
+	// Line is the line of the screenplay that caused the change.
+	Line int
+
+	SceneID   int64
+	SceneName string
+
+	// CharacterID and Character are set for AddCharacter and AddQuote.
+	CharacterID int64
+	Character   string
+
+	// Text is set for AddQuote.
+	Text string
+}
+
+// Unmatched is a speaker that doesn't match exactly one character.
+type Unmatched struct {
+	Speaker string
+	Line    int
+
+	// Candidates are the names of the characters the speaker could be. It's
+	// empty if none matched.
+	Candidates []string
+}
+
+// SceneMismatch is a numbered scene heading with a different name than the
+// scene that already has that number. The existing name is kept.
+type SceneMismatch struct {
+	Line     int
+	SceneID  int64
+	Name     string
+	Existing string
+}
+
+// Report describes what Import did, or would do in a dry run.
+type Report struct {
+	DryRun bool
+
+	Changes []*Change
+
+	// Unchanged is the number of quotes that are already in the database.
+	Unchanged int
+
+	Unmatched []*Unmatched
+
+	Mismatched []*SceneMismatch
+}
+
+// Count returns the number of changes of kind.
+func (r *Report) Count(kind ChangeKind) int {
+	n := 0
+	for _, c := range r.Changes {
+		if c.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// Import adds the scenes, scene appearances and quotes in script to the
+// database, in a single transaction.
+//
+// Speakers are matched to existing characters; characters are never created.
+// Scenes are matched by number, or by name if the heading has no number, and
+// created if they don't exist. New unnumbered scenes are numbered after every
+// scene in the database and the screenplay, so they can't take a number a
+// later heading uses. A numbered heading that names an existing scene
+// differently keeps the existing name, and is listed in the report's
+// Mismatched.
+//
+// Quotes that are already in the database are skipped, so importing the same
+// screenplay twice changes nothing. Quotes are compared by character, scene
+// and text rather than by line, and each copy in the database skips only one
+// line, so a line the screenplay repeats is still added as often as it's
+// repeated.
+//
+// If any speaker doesn't match a character, nothing is changed and Import
+// returns the report with ErrUnmatched. If dryRun is true, the report is
+// built but the transaction is rolled back.
+func Import(ctx context.Context, db *sql.DB, script *Script, dryRun bool) (*Report, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("import: %w", err)
+	}
+	defer tx.Rollback()
+
+	st, err := loadState(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("import: %w", err)
+	}
+
+	report := st.plan(script)
+	report.DryRun = dryRun
+	if len(report.Unmatched) > 0 {
+		return report, ErrUnmatched
+	}
+
+	for _, c := range report.Changes {
+		err := apply(ctx, tx, c)
+		if err != nil {
+			return nil, fmt.Errorf("import: line %d: %w", c.Line, err)
+		}
+	}
+
+	if dryRun {
+		return report, nil
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, fmt.Errorf("import: %w", err)
+	}
+
+	return report, nil
+}
+
+// state is what's in the database, updated as changes are planned.
+type state struct {
+	matcher matcher
+
+	sceneNames  map[int64]string
+	sceneByName map[string]int64
+	lastScene   int64
+
+	// appearances holds the characters in each scene.
+	appearances map[int64]map[int64]bool
+
+	// quotes counts the copies of each quote in the database, by quoteKey.
+	quotes map[string]int
+}
+
+func quoteKey(characterID, sceneID int64, text string) string {
+	return fmt.Sprintf("%d/%d/%s", characterID, sceneID, text)
+}
+
+func loadState(ctx context.Context, tx *sql.Tx) (*state, error) {
+	st := &state{
+		sceneNames:  map[int64]string{},
+		sceneByName: map[string]int64{},
+		appearances: map[int64]map[int64]bool{},
+		quotes:      map[string]int{},
+	}
+
+	err := each(ctx, tx, `SELECT id, name FROM characters ORDER BY id`, func(rows *sql.Rows) error {
+		var (
+			id   int64
+			name string
+		)
+		if err := rows.Scan(&id, &name); err != nil {
+			return err
+		}
+		st.matcher.characters = append(st.matcher.characters, newCharacter(id, name))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("load characters: %w", err)
+	}
+
+	err = each(ctx, tx, `SELECT id, name FROM scenes`, func(rows *sql.Rows) error {
+		var (
+			id   int64
+			name string
+		)
+		if err := rows.Scan(&id, &name); err != nil {
+			return err
+		}
+		st.addScene(id, name)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("load scenes: %w", err)
+	}
+
+	err = each(ctx, tx, `SELECT scene_id, character_id FROM scene_characters`, func(rows *sql.Rows) error {
+		var sceneID, characterID int64
+		if err := rows.Scan(&sceneID, &characterID); err != nil {
+			return err
+		}
+		st.inScene(sceneID)[characterID] = true
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("load scene characters: %w", err)
+	}
+
+	err = each(ctx, tx, `SELECT character_id, scene_id, text FROM quotes`, func(rows *sql.Rows) error {
+		var (
+			characterID, sceneID int64
+			text                 string
+		)
+		if err := rows.Scan(&characterID, &sceneID, &text); err != nil {
+			return err
+		}
+		st.quotes[quoteKey(characterID, sceneID, text)]++
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("load quotes: %w", err)
+	}
+
+	return st, nil
+}
+
+// each runs query and calls fn for each row.
+func each(ctx context.Context, tx *sql.Tx, query string, fn func(*sql.Rows) error) error {
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err := fn(rows)
+		if err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (st *state) addScene(id int64, name string) {
+	st.sceneNames[id] = name
+	st.sceneByName[strings.ToLower(name)] = id
+	if id > st.lastScene {
+		st.lastScene = id
+	}
+}
+
+func (st *state) inScene(sceneID int64) map[int64]bool {
+	chars := st.appearances[sceneID]
+	if chars == nil {
+		chars = map[int64]bool{}
+		st.appearances[sceneID] = chars
+	}
+	return chars
+}
+
+// plan works out the changes needed to import script.
+func (st *state) plan(script *Script) *Report {
+	report := &Report{}
+
+	for _, scene := range script.Scenes {
+		if scene.Number > st.lastScene {
+			st.lastScene = scene.Number
+		}
+	}
+
+	for _, scene := range script.Scenes {
+		sceneID, created := st.scene(scene)
+		if created {
+			report.Changes = append(report.Changes, &Change{
+				Kind:      CreateScene,
+				Line:      scene.Line,
+				SceneID:   sceneID,
+				SceneName: st.sceneNames[sceneID],
+			})
+		} else if existing := st.sceneNames[sceneID]; scene.Number != 0 && scene.Name != "" && !strings.EqualFold(scene.Name, existing) {
+			report.Mismatched = append(report.Mismatched, &SceneMismatch{
+				Line:     scene.Line,
+				SceneID:  sceneID,
+				Name:     scene.Name,
+				Existing: existing,
+			})
+		}
+
+		inScene := st.inScene(sceneID)
+		for _, line := range scene.Lines {
+			c, candidates := st.matcher.match(line.Speaker, inScene)
+			if c == nil {
+				report.Unmatched = append(report.Unmatched, &Unmatched{
+					Speaker:    line.Speaker,
+					Line:       line.Line,
+					Candidates: candidates,
+				})
+				continue
+			}
+
+			change := Change{
+				Line:        line.Line,
+				SceneID:     sceneID,
+				SceneName:   st.sceneNames[sceneID],
+				CharacterID: c.id,
+				Character:   c.name,
+			}
+
+			if !inScene[c.id] {
+				inScene[c.id] = true
+				add := change
+				add.Kind = AddCharacter
+				report.Changes = append(report.Changes, &add)
+			}
+
+			key := quoteKey(c.id, sceneID, line.Text)
+			if st.quotes[key] > 0 {
+				st.quotes[key]--
+				report.Unchanged++
+				continue
+			}
+
+			change.Kind = AddQuote
+			change.Text = line.Text
+			report.Changes = append(report.Changes, &change)
+		}
+	}
+
+	return report
+}
+
+// scene returns the ID of the scene for a heading, and whether it's new.
+func (st *state) scene(s *Scene) (int64, bool) {
+	if s.Number == 0 {
+		if id, ok := st.sceneByName[strings.ToLower(s.Name)]; ok {
+			return id, false
+		}
+
+		id := st.lastScene + 1
+		st.addScene(id, s.Name)
+		return id, true
+	}
+
+	if _, ok := st.sceneNames[s.Number]; ok {
+		return s.Number, false
+	}
+
+	name := s.Name
+	if name == "" {
+		name = fmt.Sprintf("Scene %d", s.Number)
+	}
+	st.addScene(s.Number, name)
+	return s.Number, true
+}
+
+func apply(ctx context.Context, tx *sql.Tx, c *Change) error {
+	var err error
+	switch c.Kind {
+	case CreateScene:
+		_, err = tx.ExecContext(ctx, `INSERT INTO scenes (id, name) VALUES (?, ?)`, c.SceneID, c.SceneName)
+	case AddCharacter:
+		_, err = tx.ExecContext(ctx, `INSERT INTO scene_characters (scene_id, character_id) VALUES (?, ?)`, c.SceneID, c.CharacterID)
+	case AddQuote:
+		_, err = tx.ExecContext(ctx, `INSERT INTO quotes (character_id, scene_id, text) VALUES (?, ?, ?)`, c.CharacterID, c.SceneID, c.Text)
+	default:
+		err = fmt.Errorf("unknown change %q", c.Kind)
+	}
+	return err
+}
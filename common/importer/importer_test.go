@@ -0,0 +1,207 @@
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/pboyd/godbmodels/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImport(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	db := common.TestDB(t)
+	script := parseFile(t, "testdata/bridge.txt")
+
+	before := counts(t, db)
+
+	report, err := Import(ctx, db, script, true)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.True(report.DryRun)
+	assert.Equal(1, report.Count(CreateScene))
+	assert.Equal(2, report.Count(AddCharacter))
+	assert.Equal(9, report.Count(AddQuote))
+	assert.Equal(0, report.Unchanged)
+	assert.Equal(before, counts(t, db), "dry run changed the database")
+
+	report, err = Import(ctx, db, script, false)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.False(report.DryRun)
+	assert.Equal(
+		[3]int{before[0] + 1, before[1] + 2, before[2] + 9},
+		counts(t, db),
+	)
+
+	if assert.NotEmpty(report.Changes) {
+		assert.Equal(&Change{
+			Kind:        AddQuote,
+			Line:        5,
+			SceneID:     3,
+			SceneName:   "Repression is Nine Tenths of the Law?",
+			CharacterID: 1,
+			Character:   "King Arthur",
+			Text:        "Old woman!",
+		}, report.Changes[0])
+	}
+
+	var name string
+	err = db.QueryRow(`SELECT name FROM scenes WHERE id = 24`).Scan(&name)
+	if assert.NoError(err) {
+		assert.Equal("The Black Beast of Aaaaargh", name)
+	}
+
+	var speakers []int64
+	rows, err := db.Query(`SELECT character_id FROM quotes WHERE scene_id = 24 ORDER BY id`)
+	if !assert.NoError(err) {
+		return
+	}
+	for rows.Next() {
+		var id int64
+		if assert.NoError(rows.Scan(&id)) {
+			speakers = append(speakers, id)
+		}
+	}
+	rows.Close()
+	assert.Equal([]int64{39, 28}, speakers)
+
+	// Importing again changes nothing.
+	report, err = Import(ctx, db, script, false)
+	if assert.NoError(err) {
+		assert.Empty(report.Changes)
+		assert.Equal(9, report.Unchanged)
+	}
+}
+
+func TestImportUnnumbered(t *testing.T) {
+	assert := assert.New(t)
+	db := common.TestDB(t)
+
+	script, err := Parse(strings.NewReader("SCENE: Camelot\nARTHUR: On second thought, let's not go to Camelot.\n\nSCENE: Camelot\nPATSY: It's only a model."))
+	if !assert.NoError(err) {
+		return
+	}
+
+	report, err := Import(context.Background(), db, script, false)
+	if !assert.NoError(err) || !assert.Equal(1, report.Count(CreateScene)) {
+		return
+	}
+	assert.Equal(int64(24), report.Changes[0].SceneID)
+	assert.Equal(2, report.Count(AddCharacter))
+	assert.Equal(2, report.Count(AddQuote))
+}
+
+func TestImportSceneNumbers(t *testing.T) {
+	assert := assert.New(t)
+	db := common.TestDB(t)
+
+	// The unnumbered heading comes first, but mustn't take scene 24.
+	script, err := Parse(strings.NewReader("SCENE: Camelot\nARTHUR: On second thought, let's not go to Camelot.\n\nSCENE 24: The Cave\nARTHUR: Run away!\n\nSCENE 6: Camelot\nPATSY: It's only a model."))
+	if !assert.NoError(err) {
+		return
+	}
+
+	report, err := Import(context.Background(), db, script, true)
+	if !assert.NoError(err) {
+		return
+	}
+
+	var created []int64
+	for _, c := range report.Changes {
+		if c.Kind == CreateScene {
+			created = append(created, c.SceneID)
+		}
+	}
+	assert.Equal([]int64{25, 24}, created)
+
+	assert.Equal([]*SceneMismatch{{
+		Line:     7,
+		SceneID:  6,
+		Name:     "Camelot",
+		Existing: "Camelot is a Silly Place",
+	}}, report.Mismatched)
+}
+
+func TestImportRepeated(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	db := common.TestDB(t)
+
+	script, err := Parse(strings.NewReader("SCENE 24: The Cave\nARTHUR: Run away!\n\nROBIN: Run away!\n\nARTHUR: Run away!"))
+	if !assert.NoError(err) {
+		return
+	}
+
+	report, err := Import(ctx, db, script, false)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Equal(3, report.Count(AddQuote))
+	assert.Equal(0, report.Unchanged)
+
+	report, err = Import(ctx, db, script, false)
+	if assert.NoError(err) {
+		assert.Empty(report.Changes)
+		assert.Equal(3, report.Unchanged)
+	}
+}
+
+func TestImportUnmatched(t *testing.T) {
+	assert := assert.New(t)
+	db := common.TestDB(t)
+	before := counts(t, db)
+
+	script, err := Parse(strings.NewReader("SCENE 24: The Cave\nARTHUR: Run away!\nBRIAN: He's not the Messiah.\nKNIGHT: What?"))
+	if !assert.NoError(err) {
+		return
+	}
+
+	report, err := Import(context.Background(), db, script, false)
+	assert.True(errors.Is(err, ErrUnmatched))
+	if assert.NotNil(report) && assert.Len(report.Unmatched, 2) {
+		assert.Equal(&Unmatched{Speaker: "BRIAN", Line: 3}, report.Unmatched[0])
+		assert.Equal("KNIGHT", report.Unmatched[1].Speaker)
+		assert.Contains(report.Unmatched[1].Candidates, "Knight of Camelot")
+	}
+	assert.Equal(before, counts(t, db))
+}
+
+func parseFile(t *testing.T, path string) *Script {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	script, err := Parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return script
+}
+
+// counts returns the number of scenes, scene_characters and quotes rows.
+func counts(t *testing.T, db *sql.DB) [3]int {
+	t.Helper()
+
+	var n [3]int
+	for i, table := range []string{"scenes", "scene_characters", "quotes"} {
+		err := db.QueryRow(`SELECT COUNT(*) FROM ` + table).Scan(&n[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return n
+}
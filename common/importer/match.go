@@ -0,0 +1,167 @@
+package importer
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// character is a character from the database, with its name prepared for
+// matching.
+type character struct {
+	id    int64
+	name  string
+	norm  string
+	words []string
+}
+
+func newCharacter(id int64, name string) *character {
+	norm := normalize(name)
+	return &character{id: id, name: name, norm: norm, words: strings.Fields(norm)}
+}
+
+// normalize lowercases s and replaces punctuation with spaces.
+func normalize(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		if r == '\'' {
+			return -1
+		}
+		return ' '
+	}, s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// matcher finds the character a speaker refers to.
+type matcher struct {
+	characters []*character
+}
+
+// match returns the character speaker refers to. Screenplays usually use a
+// short form of the name ("ARTHUR" for "King Arthur"), and sometimes spell it
+// differently, so match tries each of these in turn:
+//
+//  1. The whole name, ignoring case and punctuation.
+//  2. Every word of the speaker is a word of the name.
+//  3. Every word of the speaker is close to a word of the name.
+//
+// When more than one character matches, the ones in inScene are preferred.
+// If that doesn't narrow it down to one, match returns nil along with the
+// names of the characters that matched.
+func (m *matcher) match(speaker string, inScene map[int64]bool) (*character, []string) {
+	norm := normalize(speaker)
+	words := strings.Fields(norm)
+	if len(words) == 0 {
+		return nil, nil
+	}
+
+	steps := []func(c *character) bool{
+		func(c *character) bool { return c.norm == norm },
+		func(c *character) bool { return containsWords(c.words, words, 0) },
+		func(c *character) bool { return containsWords(c.words, words, -1) },
+	}
+
+	for _, step := range steps {
+		var candidates []*character
+		for _, c := range m.characters {
+			if step(c) {
+				candidates = append(candidates, c)
+			}
+		}
+
+		if len(candidates) == 0 {
+			continue
+		}
+		if len(candidates) == 1 {
+			return candidates[0], nil
+		}
+
+		var present []*character
+		for _, c := range candidates {
+			if inScene[c.id] {
+				present = append(present, c)
+			}
+		}
+		if len(present) == 1 {
+			return present[0], nil
+		}
+
+		return nil, names(candidates)
+	}
+
+	return nil, nil
+}
+
+// containsWords returns true if each word in want is within maxDistance edits
+// of a word in have. A negative maxDistance allows one edit for every four
+// letters, so short words must match exactly.
+func containsWords(have, want []string, maxDistance int) bool {
+	for _, w := range want {
+		limit := maxDistance
+		if limit < 0 {
+			limit = len([]rune(w)) / 4
+		}
+
+		found := false
+		for _, h := range have {
+			if distance(w, h) <= limit {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// distance returns the Levenshtein distance between a and b.
+func distance(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	prev := make([]int, len(br)+1)
+	cur := make([]int, len(br)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ar); i++ {
+		cur[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev[len(br)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+func names(characters []*character) []string {
+	seen := map[string]bool{}
+	var list []string
+	for _, c := range characters {
+		if !seen[c.name] {
+			seen[c.name] = true
+			list = append(list, c.name)
+		}
+	}
+	sort.Strings(list)
+	return list
+}
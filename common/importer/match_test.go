@@ -0,0 +1,62 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch(t *testing.T) {
+	m := &matcher{}
+	for i, name := range []string{
+		"King Arthur",
+		"Sir Bedevere",
+		"Dennis",
+		"Dennis's Mother",
+		"Knight of Camelot",
+		"Knight of Camelot",
+		"Green Knight",
+	} {
+		m.characters = append(m.characters, newCharacter(int64(i+1), name))
+	}
+
+	cases := map[string]struct {
+		speaker    string
+		inScene    map[int64]bool
+		expected   int64
+		candidates []string
+	}{
+		"Exact":      {speaker: "DENNIS", expected: 3},
+		"Words":      {speaker: "ARTHUR", expected: 1},
+		"Possessive": {speaker: "DENNIS'S MOTHER", expected: 4},
+		"Spelling":   {speaker: "BEDIVERE", expected: 2},
+		"Missing":    {speaker: "TIM"},
+		"Short":      {speaker: "KING ARTHRU"},
+		"Ambiguous": {
+			speaker:    "KNIGHT",
+			candidates: []string{"Green Knight", "Knight of Camelot"},
+		},
+		"InScene": {
+			speaker:  "KNIGHT",
+			inScene:  map[int64]bool{1: true, 7: true},
+			expected: 7,
+		},
+		"SameName": {
+			speaker:  "KNIGHT OF CAMELOT",
+			inScene:  map[int64]bool{6: true},
+			expected: 6,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c, candidates := m.match(tc.speaker, tc.inScene)
+			if tc.expected == 0 {
+				assert.Nil(t, c)
+			} else if assert.NotNil(t, c) {
+				assert.Equal(t, tc.expected, c.id)
+			}
+			assert.Equal(t, tc.candidates, candidates)
+		})
+	}
+}
@@ -0,0 +1,134 @@
+// Package importer loads scenes and quotes from a screenplay into the
+// database.
+//
+// The screenplay is plain text:
+//
+//	SCENE 3: Repression is Nine Tenths of the Law?
+//	[Arthur rides up to a peasant]
+//	ARTHUR: Old woman!
+//	DENNIS: Man!
+//
+// A scene heading is the word SCENE, followed by an optional scene number and
+// an optional name after a colon. Each "SPEAKER: text" line is a quote, and
+// the speaker must be in capitals. Lines that follow a quote, without a blank
+// line in between, continue it. Stage directions in [brackets] or
+// (parentheses) are ignored, as are lines starting with #.
+package importer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Script is a parsed screenplay.
+type Script struct {
+	Scenes []*Scene
+}
+
+// Scene is one scene of a screenplay.
+type Scene struct {
+	// Number is the scene number from the heading, or 0 if it had none.
+	Number int64
+
+	// Name is the scene name from the heading, if it had one.
+	Name string
+
+	// Line is the line number of the heading.
+	Line int
+
+	Lines []*Line
+}
+
+// Line is something a character says.
+type Line struct {
+	// Speaker is the name the script gives the character.
+	Speaker string
+
+	Text string
+
+	// Line is the line number where the text starts.
+	Line int
+}
+
+// ParseError is returned by Parse when the screenplay has a line it doesn't
+// understand.
+type ParseError struct {
+	Line int
+	Text string
+	Msg  string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %s: %q", e.Line, e.Msg, e.Text)
+}
+
+var (
+	sceneHeading = regexp.MustCompile(`^(?i:scene)(?:\s+(\d+))?\s*(?::\s*(.*))?$`)
+	dialogue     = regexp.MustCompile(`^([A-Z][A-Z0-9 .'#-]*?)\s*:\s*(.+)$`)
+)
+
+// Parse reads a screenplay.
+func Parse(r io.Reader) (*Script, error) {
+	var (
+		script  Script
+		scene   *Scene
+		current *Line
+		lineNo  int
+	)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lineNo++
+		text := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case text == "":
+			current = nil
+
+		case strings.HasPrefix(text, "#"), isDirection(text):
+			// Comments and stage directions don't end a quote, since
+			// directions often interrupt one.
+
+		case sceneHeading.MatchString(text):
+			m := sceneHeading.FindStringSubmatch(text)
+			scene = &Scene{Name: strings.TrimSpace(m[2]), Line: lineNo}
+			if m[1] != "" {
+				scene.Number, _ = strconv.ParseInt(m[1], 10, 64)
+			}
+			if scene.Number == 0 && scene.Name == "" {
+				return nil, &ParseError{Line: lineNo, Text: text, Msg: "scene heading needs a number or a name"}
+			}
+			script.Scenes = append(script.Scenes, scene)
+			current = nil
+
+		case dialogue.MatchString(text):
+			if scene == nil {
+				return nil, &ParseError{Line: lineNo, Text: text, Msg: "dialogue before the first scene heading"}
+			}
+
+			m := dialogue.FindStringSubmatch(text)
+			current = &Line{Speaker: m[1], Text: m[2], Line: lineNo}
+			scene.Lines = append(scene.Lines, current)
+
+		case current != nil:
+			current.Text += " " + text
+
+		default:
+			return nil, &ParseError{Line: lineNo, Text: text, Msg: "expected a scene heading or dialogue"}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return &script, nil
+}
+
+func isDirection(text string) bool {
+	return (strings.HasPrefix(text, "[") && strings.HasSuffix(text, "]")) ||
+		(strings.HasPrefix(text, "(") && strings.HasSuffix(text, ")"))
+}
@@ -0,0 +1,86 @@
+package importer
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	assert := assert.New(t)
+
+	f, err := os.Open("testdata/bridge.txt")
+	if !assert.NoError(err) {
+		return
+	}
+	defer f.Close()
+
+	script, err := Parse(f)
+	if !assert.NoError(err) || !assert.Len(script.Scenes, 3) {
+		return
+	}
+
+	s := script.Scenes[1]
+	assert.Equal(int64(22), s.Number)
+	assert.Equal("The Bridge Keeper Doesn't Swallow", s.Name)
+	assert.Equal(9, s.Line)
+	if assert.Len(s.Lines, 4) {
+		assert.Equal(&Line{
+			Speaker: "BRIDGEKEEPER",
+			Text:    "Stop! Who would cross the Bridge of Death must answer me these questions three, ere the other side he see.",
+			Line:    10,
+		}, s.Lines[0])
+		assert.Equal("ROBIN", s.Lines[2].Speaker)
+	}
+}
+
+func TestParseHeadings(t *testing.T) {
+	cases := map[string]*Scene{
+		"SCENE 5":              {Number: 5, Line: 1},
+		"Scene 5: Witches":     {Number: 5, Name: "Witches", Line: 1},
+		"SCENE: The Holy Hand": {Name: "The Holy Hand", Line: 1},
+	}
+
+	for heading, expected := range cases {
+		t.Run(heading, func(t *testing.T) {
+			script, err := Parse(strings.NewReader(heading))
+			if assert.NoError(t, err) && assert.Len(t, script.Scenes, 1) {
+				assert.Equal(t, expected, script.Scenes[0])
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := map[string]struct {
+		script string
+		line   int
+	}{
+		"NoScene": {
+			script: "ARTHUR: Old woman!",
+			line:   1,
+		},
+		"EmptyHeading": {
+			script: "SCENE",
+			line:   1,
+		},
+		"Stray": {
+			script: "SCENE 3\n\nthe peasant keeps digging",
+			line:   3,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tc.script))
+
+			var pe *ParseError
+			if assert.True(t, errors.As(err, &pe), "%v", err) {
+				assert.Equal(t, tc.line, pe.Line)
+			}
+		})
+	}
+}
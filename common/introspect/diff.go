@@ -0,0 +1,412 @@
+package introspect
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Kind is the kind of a Difference.
+type Kind int
+
+const (
+	// MissingTable is a table that's expected but isn't there.
+	MissingTable Kind = iota
+
+	// ExtraTable is a table that isn't expected.
+	ExtraTable
+
+	// MissingColumn is a column that's expected but isn't there.
+	MissingColumn
+
+	// ExtraColumn is a column that isn't expected.
+	ExtraColumn
+
+	// ColumnType is a column with the wrong type.
+	ColumnType
+
+	// ColumnConstraint is a column that differs in NOT NULL, the primary
+	// key or its default value.
+	ColumnConstraint
+
+	// MissingForeignKey is a foreign key that's expected but isn't there.
+	MissingForeignKey
+
+	// ExtraForeignKey is a foreign key that isn't expected.
+	ExtraForeignKey
+
+	// MissingIndex is an index that's expected but isn't there.
+	MissingIndex
+
+	// ExtraIndex is an index that isn't expected.
+	ExtraIndex
+)
+
+// Difference is one way that a schema differs from what was expected.
+type Difference struct {
+	Kind  Kind
+	Table string
+
+	// Name is the column, foreign key or index that differs. It's empty
+	// for table differences.
+	Name string
+
+	// Expected and Actual describe the mismatch for ColumnType and
+	// ColumnConstraint differences.
+	Expected string
+	Actual   string
+}
+
+func (d Difference) String() string {
+	switch d.Kind {
+	case MissingTable:
+		return "missing table " + d.Table
+	case ExtraTable:
+		return "unexpected table " + d.Table
+	case MissingColumn:
+		return fmt.Sprintf("missing column %s.%s", d.Table, d.Name)
+	case ExtraColumn:
+		return fmt.Sprintf("unexpected column %s.%s", d.Table, d.Name)
+	case ColumnType, ColumnConstraint:
+		return fmt.Sprintf("column %s.%s is %s, expected %s", d.Table, d.Name, d.Actual, d.Expected)
+	case MissingForeignKey:
+		return fmt.Sprintf("missing foreign key %s %s", d.Table, d.Name)
+	case ExtraForeignKey:
+		return fmt.Sprintf("unexpected foreign key %s %s", d.Table, d.Name)
+	case MissingIndex:
+		return fmt.Sprintf("missing index on %s %s", d.Table, d.Name)
+	case ExtraIndex:
+		return fmt.Sprintf("unexpected index on %s %s", d.Table, d.Name)
+	default:
+		return fmt.Sprintf("unknown difference in %s %s", d.Table, d.Name)
+	}
+}
+
+// Diff compares actual to expected. It returns nothing if they match.
+//
+// Types are compared as declared, ignoring case. Foreign keys and indexes are
+// compared by what they cover rather than by name, since SQLite names the
+// indexes it creates itself.
+func Diff(expected, actual *Schema) []Difference {
+	var diffs []Difference
+
+	for _, et := range expected.Tables {
+		at := actual.Table(et.Name)
+		if at == nil {
+			diffs = append(diffs, Difference{Kind: MissingTable, Table: et.Name})
+			continue
+		}
+		diffs = append(diffs, diffTable(et, at)...)
+	}
+
+	for _, at := range actual.Tables {
+		if expected.Table(at.Name) == nil {
+			diffs = append(diffs, Difference{Kind: ExtraTable, Table: at.Name})
+		}
+	}
+
+	return diffs
+}
+
+func diffTable(expected, actual *Table) []Difference {
+	var diffs []Difference
+
+	for _, ec := range expected.Columns {
+		ac := actual.Column(ec.Name)
+		if ac == nil {
+			diffs = append(diffs, Difference{Kind: MissingColumn, Table: expected.Name, Name: ec.Name})
+			continue
+		}
+		diffs = append(diffs, diffColumn(expected.Name, ec, ac)...)
+	}
+	for _, ac := range actual.Columns {
+		if expected.Column(ac.Name) == nil {
+			diffs = append(diffs, Difference{Kind: ExtraColumn, Table: expected.Name, Name: ac.Name})
+		}
+	}
+
+	diffs = append(diffs, diffSets(expected.Name, MissingForeignKey, ExtraForeignKey, stringers(expected.ForeignKeys), stringers(actual.ForeignKeys))...)
+	diffs = append(diffs, diffSets(expected.Name, MissingIndex, ExtraIndex, stringers(expected.Indexes), stringers(actual.Indexes))...)
+
+	return diffs
+}
+
+func diffColumn(table string, expected, actual *Column) []Difference {
+	var diffs []Difference
+
+	if !strings.EqualFold(expected.Type, actual.Type) {
+		diffs = append(diffs, Difference{
+			Kind:     ColumnType,
+			Table:    table,
+			Name:     expected.Name,
+			Expected: expected.Type,
+			Actual:   actual.Type,
+		})
+	}
+
+	if expected.NotNull != actual.NotNull {
+		diffs = append(diffs, constraintDiff(table, expected.Name, nullability(expected), nullability(actual)))
+	}
+	if expected.PrimaryKey != actual.PrimaryKey {
+		diffs = append(diffs, constraintDiff(table, expected.Name, primaryKey(expected), primaryKey(actual)))
+	}
+	if defaultValue(expected) != defaultValue(actual) {
+		diffs = append(diffs, constraintDiff(table, expected.Name, defaultValue(expected), defaultValue(actual)))
+	}
+
+	return diffs
+}
+
+func constraintDiff(table, column, expected, actual string) Difference {
+	return Difference{
+		Kind:     ColumnConstraint,
+		Table:    table,
+		Name:     column,
+		Expected: expected,
+		Actual:   actual,
+	}
+}
+
+func nullability(c *Column) string {
+	if c.NotNull {
+		return "NOT NULL"
+	}
+	return "NULL"
+}
+
+func primaryKey(c *Column) string {
+	if c.PrimaryKey == 0 {
+		return "not in the primary key"
+	}
+	return fmt.Sprintf("primary key column %d", c.PrimaryKey)
+}
+
+func defaultValue(c *Column) string {
+	if c.Default == nil {
+		return "without a default"
+	}
+	return "DEFAULT " + *c.Default
+}
+
+func stringers[T fmt.Stringer](items []T) []string {
+	s := make([]string, len(items))
+	for i, item := range items {
+		s[i] = item.String()
+	}
+	return s
+}
+
+// diffSets reports the items that are only in expected or only in actual.
+func diffSets(table string, missing, extra Kind, expected, actual []string) []Difference {
+	var diffs []Difference
+
+	count := map[string]int{}
+	for _, s := range actual {
+		count[s]++
+	}
+	for _, s := range expected {
+		if count[s] > 0 {
+			count[s]--
+			continue
+		}
+		diffs = append(diffs, Difference{Kind: missing, Table: table, Name: s})
+	}
+
+	for _, s := range actual {
+		if count[s] > 0 {
+			count[s]--
+			diffs = append(diffs, Difference{Kind: extra, Table: table, Name: s})
+		}
+	}
+
+	return diffs
+}
+
+// Field is a struct field that a model package maps to a column.
+type Field struct {
+	// Name is the Go field name.
+	Name string
+
+	// Column is the column name. If it's empty, the column is found by
+	// comparing the names without case or underscores, so ActorID matches
+	// actor_id.
+	Column string
+
+	Type reflect.Type
+}
+
+// StructFields returns the exported fields of a struct, or a pointer to one,
+// that could hold a column. Fields that are structs (other than time.Time
+// and the sql.Null types) or slices (other than []byte) are assumed to be
+// relationships and are skipped.
+func StructFields(v interface{}) []Field {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var fields []Field
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() || !isScalar(f.Type) {
+			continue
+		}
+		fields = append(fields, Field{Name: f.Name, Type: f.Type})
+	}
+
+	return fields
+}
+
+// CheckFields compares a model's fields with the table they're stored in.
+// The table is what's expected, so a column without a field is reported as
+// MissingColumn and a field without a column as ExtraColumn. A field whose
+// type can't hold the column's values is a ColumnType difference, and a
+// field that can't hold NULL for a nullable column is a ColumnConstraint
+// difference.
+func (t *Table) CheckFields(fields []Field) []Difference {
+	var diffs []Difference
+	mapped := map[string]bool{}
+
+	for _, f := range fields {
+		c := t.findColumn(f)
+		if c == nil {
+			name := f.Column
+			if name == "" {
+				name = f.Name
+			}
+			diffs = append(diffs, Difference{Kind: ExtraColumn, Table: t.Name, Name: name})
+			continue
+		}
+		mapped[c.Name] = true
+
+		base, nullable := unwrapNullable(f.Type)
+		if !compatible(affinity(c.Type), base) {
+			diffs = append(diffs, Difference{
+				Kind:     ColumnType,
+				Table:    t.Name,
+				Name:     c.Name,
+				Expected: "a type for " + f.Type.String(),
+				Actual:   c.Type,
+			})
+		}
+		if c.Nullable() && !nullable {
+			diffs = append(diffs, constraintDiff(t.Name, c.Name, "NOT NULL (for "+f.Type.String()+")", "NULL"))
+		}
+	}
+
+	for _, c := range t.Columns {
+		if !mapped[c.Name] {
+			diffs = append(diffs, Difference{Kind: MissingColumn, Table: t.Name, Name: c.Name})
+		}
+	}
+
+	return diffs
+}
+
+func (t *Table) findColumn(f Field) *Column {
+	if f.Column != "" {
+		return t.Column(f.Column)
+	}
+
+	name := normalize(f.Name)
+	for _, c := range t.Columns {
+		if normalize(c.Name) == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func normalize(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
+
+var (
+	timeType  = reflect.TypeOf(time.Time{})
+	bytesType = reflect.TypeOf([]byte(nil))
+
+	// nullTypes maps the sql.Null types to the type they hold.
+	nullTypes = map[reflect.Type]reflect.Type{
+		reflect.TypeOf(sql.NullBool{}):    reflect.TypeOf(false),
+		reflect.TypeOf(sql.NullByte{}):    reflect.TypeOf(byte(0)),
+		reflect.TypeOf(sql.NullFloat64{}): reflect.TypeOf(float64(0)),
+		reflect.TypeOf(sql.NullInt16{}):   reflect.TypeOf(int16(0)),
+		reflect.TypeOf(sql.NullInt32{}):   reflect.TypeOf(int32(0)),
+		reflect.TypeOf(sql.NullInt64{}):   reflect.TypeOf(int64(0)),
+		reflect.TypeOf(sql.NullString{}):  reflect.TypeOf(""),
+		reflect.TypeOf(sql.NullTime{}):    timeType,
+	}
+)
+
+func isScalar(t reflect.Type) bool {
+	base, _ := unwrapNullable(t)
+	switch base.Kind() {
+	case reflect.Struct:
+		return base == timeType
+	case reflect.Slice:
+		return base == bytesType
+	case reflect.Map, reflect.Array, reflect.Chan, reflect.Func, reflect.Interface:
+		return false
+	default:
+		return true
+	}
+}
+
+// unwrapNullable returns the type held by a pointer or sql.Null type, and
+// whether t can hold NULL.
+func unwrapNullable(t reflect.Type) (reflect.Type, bool) {
+	if t.Kind() == reflect.Pointer {
+		return t.Elem(), true
+	}
+	if base, ok := nullTypes[t]; ok {
+		return base, true
+	}
+	if t == bytesType {
+		return t, true
+	}
+	return t, false
+}
+
+// affinity returns the SQLite type affinity of a declared type, following
+// the rules in https://www.sqlite.org/datatype3.html.
+func affinity(declared string) string {
+	t := strings.ToUpper(declared)
+	switch {
+	case strings.Contains(t, "INT"):
+		return "INTEGER"
+	case strings.Contains(t, "CHAR"), strings.Contains(t, "CLOB"), strings.Contains(t, "TEXT"):
+		return "TEXT"
+	case t == "", strings.Contains(t, "BLOB"):
+		return "BLOB"
+	case strings.Contains(t, "REAL"), strings.Contains(t, "FLOA"), strings.Contains(t, "DOUB"):
+		return "REAL"
+	default:
+		return "NUMERIC"
+	}
+}
+
+// compatible reports whether a Go type can hold the values of a column with
+// the given affinity.
+func compatible(affinity string, t reflect.Type) bool {
+	if t == timeType {
+		return affinity == "NUMERIC" || affinity == "TEXT" || affinity == "INTEGER"
+	}
+	if t == bytesType {
+		return affinity == "BLOB" || affinity == "TEXT"
+	}
+
+	switch t.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return affinity == "INTEGER" || affinity == "NUMERIC"
+	case reflect.Float32, reflect.Float64:
+		return affinity == "REAL" || affinity == "NUMERIC" || affinity == "INTEGER"
+	case reflect.String:
+		return affinity == "TEXT"
+	default:
+		return false
+	}
+}
@@ -0,0 +1,315 @@
+// Package introspect reads the schema of a SQLite database and compares it to
+// the schema the migrations create, or to the Go structs that the model
+// packages map onto it.
+package introspect
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/pboyd/godbmodels/common"
+)
+
+// Querier is implemented by *sql.DB, *sql.Tx and *sql.Conn.
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// Schema is the set of tables in a database.
+type Schema struct {
+	// Tables are sorted by name.
+	Tables []*Table
+}
+
+// Table finds a table by name. It returns nil if there is no such table.
+func (s *Schema) Table(name string) *Table {
+	for _, t := range s.Tables {
+		if t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// Table is one table from sqlite_master.
+type Table struct {
+	Name string
+
+	// SQL is the CREATE TABLE statement.
+	SQL string
+
+	// Columns are in the order they were declared.
+	Columns []*Column
+
+	ForeignKeys []*ForeignKey
+	Indexes     []*Index
+}
+
+// Column finds a column by name. It returns nil if there is no such column.
+func (t *Table) Column(name string) *Column {
+	for _, c := range t.Columns {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// Column is a column from PRAGMA table_info.
+type Column struct {
+	Name string
+
+	// Type is the declared type, which may be empty.
+	Type string
+
+	NotNull bool
+
+	// Default is the default value as written in the schema, or nil if
+	// there isn't one.
+	Default *string
+
+	// PrimaryKey is the column's position in the primary key, starting
+	// at 1, or 0 if it isn't part of the primary key.
+	PrimaryKey int
+}
+
+// Nullable is true if the column can hold NULL. An INTEGER PRIMARY KEY is
+// an alias for the rowid, so it's never NULL even though SQLite doesn't
+// report it as NOT NULL.
+func (c *Column) Nullable() bool {
+	return !c.NotNull && c.PrimaryKey == 0
+}
+
+// ForeignKey is a foreign key from PRAGMA foreign_key_list.
+type ForeignKey struct {
+	Columns    []string
+	Table      string
+	References []string
+	OnUpdate   string
+	OnDelete   string
+}
+
+// String returns the foreign key as it would be written in CREATE TABLE.
+// Actions are left out when they're the default, NO ACTION.
+func (fk *ForeignKey) String() string {
+	s := fmt.Sprintf("(%s) REFERENCES %s (%s)", strings.Join(fk.Columns, ", "), fk.Table, strings.Join(fk.References, ", "))
+	if fk.OnUpdate != "" && fk.OnUpdate != "NO ACTION" {
+		s += " ON UPDATE " + fk.OnUpdate
+	}
+	if fk.OnDelete != "" && fk.OnDelete != "NO ACTION" {
+		s += " ON DELETE " + fk.OnDelete
+	}
+	return s
+}
+
+// Index is an index from PRAGMA index_list, including the ones SQLite
+// creates for primary keys and unique constraints.
+type Index struct {
+	Name    string
+	Unique  bool
+	Partial bool
+
+	// Origin is "c" for CREATE INDEX, "u" for a UNIQUE constraint and "pk"
+	// for a PRIMARY KEY.
+	Origin string
+
+	Columns []string
+}
+
+// String describes the index without its name.
+func (ix *Index) String() string {
+	s := "(" + strings.Join(ix.Columns, ", ") + ")"
+	if ix.Unique {
+		s = "UNIQUE " + s
+	}
+	if ix.Partial {
+		s += " WHERE ..."
+	}
+	return s
+}
+
+// Read loads the schema of a database. SQLite's own tables are skipped, as
+// are virtual tables, like the full-text index, and the tables that back them.
+func Read(ctx context.Context, db Querier) (*Schema, error) {
+	tables, err := readTables(ctx, db)
+	if err != nil {
+		return nil, fmt.Errorf("read tables: %w", err)
+	}
+
+	// Each table is read after the list is closed, since a *sql.Tx or
+	// *sql.Conn can't run a query while another one is open.
+	for _, t := range tables {
+		t.Columns, err = readColumns(ctx, db, t.Name)
+		if err != nil {
+			return nil, fmt.Errorf("read columns of %s: %w", t.Name, err)
+		}
+
+		t.ForeignKeys, err = readForeignKeys(ctx, db, t.Name)
+		if err != nil {
+			return nil, fmt.Errorf("read foreign keys of %s: %w", t.Name, err)
+		}
+
+		t.Indexes, err = readIndexes(ctx, db, t.Name)
+		if err != nil {
+			return nil, fmt.Errorf("read indexes of %s: %w", t.Name, err)
+		}
+	}
+
+	return &Schema{Tables: tables}, nil
+}
+
+// Expected returns the schema that the migrations create, by migrating an
+// empty in-memory database.
+func Expected(ctx context.Context) (*Schema, error) {
+	db, err := sql.Open(common.DriverName, common.DSN(":memory:"))
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	err = common.Migrate(ctx, db, common.Latest)
+	if err != nil {
+		return nil, err
+	}
+
+	return Read(ctx, db)
+}
+
+func readTables(ctx context.Context, db Querier) ([]*Table, error) {
+	rows, err := db.QueryContext(ctx, `SELECT m.name, COALESCE(m.sql, '')
+		FROM sqlite_master m
+		JOIN pragma_table_list t ON t.schema = 'main' AND t.name = m.name
+		WHERE m.type = 'table' AND t.type = 'table' AND m.name NOT LIKE 'sqlite\_%' ESCAPE '\'
+		ORDER BY m.name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tables []*Table
+	for rows.Next() {
+		var t Table
+		err := rows.Scan(&t.Name, &t.SQL)
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, &t)
+	}
+
+	return tables, rows.Err()
+}
+
+func readColumns(ctx context.Context, db Querier, table string) ([]*Column, error) {
+	rows, err := db.QueryContext(ctx, `SELECT name, type, "notnull", dflt_value, pk FROM pragma_table_info(?) ORDER BY cid`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []*Column
+	for rows.Next() {
+		var (
+			c    Column
+			dflt sql.NullString
+		)
+		err := rows.Scan(&c.Name, &c.Type, &c.NotNull, &dflt, &c.PrimaryKey)
+		if err != nil {
+			return nil, err
+		}
+		if dflt.Valid {
+			c.Default = &dflt.String
+		}
+		columns = append(columns, &c)
+	}
+
+	return columns, rows.Err()
+}
+
+func readForeignKeys(ctx context.Context, db Querier, table string) ([]*ForeignKey, error) {
+	rows, err := db.QueryContext(ctx, `SELECT id, "table", "from", COALESCE("to", ''), on_update, on_delete FROM pragma_foreign_key_list(?) ORDER BY id, seq`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		keys   []*ForeignKey
+		lastID = -1
+	)
+	for rows.Next() {
+		var (
+			id       int
+			fk       ForeignKey
+			from, to string
+		)
+		err := rows.Scan(&id, &fk.Table, &from, &to, &fk.OnUpdate, &fk.OnDelete)
+		if err != nil {
+			return nil, err
+		}
+
+		// Each column of a composite key is a separate row.
+		if id != lastID {
+			keys = append(keys, &fk)
+			lastID = id
+		}
+		last := keys[len(keys)-1]
+		last.Columns = append(last.Columns, from)
+		last.References = append(last.References, to)
+	}
+
+	return keys, rows.Err()
+}
+
+func readIndexes(ctx context.Context, db Querier, table string) ([]*Index, error) {
+	rows, err := db.QueryContext(ctx, `SELECT name, "unique", origin, partial FROM pragma_index_list(?) ORDER BY name`, table)
+	if err != nil {
+		return nil, err
+	}
+
+	var indexes []*Index
+	for rows.Next() {
+		var ix Index
+		err := rows.Scan(&ix.Name, &ix.Unique, &ix.Origin, &ix.Partial)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		indexes = append(indexes, &ix)
+	}
+	rows.Close()
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	for _, ix := range indexes {
+		ix.Columns, err = readIndexColumns(ctx, db, ix.Name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return indexes, nil
+}
+
+func readIndexColumns(ctx context.Context, db Querier, index string) ([]string, error) {
+	rows, err := db.QueryContext(ctx, `SELECT COALESCE(name, '') FROM pragma_index_info(?) ORDER BY seqno`, index)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var name string
+		err := rows.Scan(&name)
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, name)
+	}
+
+	return columns, rows.Err()
+}
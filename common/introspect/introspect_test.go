@@ -0,0 +1,156 @@
+package introspect_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pboyd/godbmodels/common"
+	"github.com/pboyd/godbmodels/common/introspect"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRead(t *testing.T) {
+	assert := assert.New(t)
+
+	schema, err := introspect.Read(context.Background(), common.TestTx(t))
+	if !assert.NoError(err) {
+		return
+	}
+
+	var names []string
+	for _, t := range schema.Tables {
+		names = append(names, t.Name)
+	}
+	assert.Equal([]string{"actors", "characters", "quotes", "scene_characters", "scenes", "schema_migrations"}, names)
+
+	characters := schema.Table("characters")
+	if !assert.NotNil(characters) {
+		return
+	}
+	assert.Contains(characters.SQL, "CREATE TABLE characters")
+	assert.Equal(&introspect.Column{Name: "id", Type: "INTEGER", PrimaryKey: 1}, characters.Column("id"))
+	assert.Equal(&introspect.Column{Name: "name", Type: "TEXT", NotNull: true}, characters.Column("name"))
+	if assert.Len(characters.ForeignKeys, 1) {
+		assert.Equal("(actor_id) REFERENCES actors (id)", characters.ForeignKeys[0].String())
+	}
+
+	sc := schema.Table("scene_characters")
+	if assert.NotNil(sc) && assert.Len(sc.Indexes, 1) {
+		assert.Equal("pk", sc.Indexes[0].Origin)
+		assert.Equal("UNIQUE (scene_id, character_id)", sc.Indexes[0].String())
+	}
+
+	migrations := schema.Table("schema_migrations")
+	if assert.NotNil(migrations) {
+		appliedAt := migrations.Column("applied_at")
+		if assert.NotNil(appliedAt) && assert.NotNil(appliedAt.Default) {
+			assert.Equal("CURRENT_TIMESTAMP", *appliedAt.Default)
+		}
+	}
+}
+
+func TestDiffMatches(t *testing.T) {
+	assert := assert.New(t)
+
+	expected, err := introspect.Expected(context.Background())
+	if !assert.NoError(err) {
+		return
+	}
+
+	actual, err := introspect.Read(context.Background(), common.TestTx(t))
+	if !assert.NoError(err) {
+		return
+	}
+
+	assert.Empty(introspect.Diff(expected, actual))
+}
+
+func TestDiff(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	db := common.TestEmptyDB(t)
+	_, err := db.Exec(`
+		DROP TABLE quotes;
+		CREATE TABLE quotes (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			character_id INTEGER NOT NULL,
+			scene_id TEXT,
+			FOREIGN KEY (character_id) REFERENCES characters (id)
+		);
+		DROP TABLE scene_characters;
+		CREATE TABLE extras (id INTEGER);
+		CREATE INDEX characters_name ON characters (name);
+	`)
+	if !assert.NoError(err) {
+		return
+	}
+
+	expected, err := introspect.Expected(ctx)
+	if !assert.NoError(err) {
+		return
+	}
+
+	actual, err := introspect.Read(ctx, db)
+	if !assert.NoError(err) {
+		return
+	}
+
+	var diffs []string
+	for _, d := range introspect.Diff(expected, actual) {
+		diffs = append(diffs, d.String())
+	}
+
+	assert.Equal([]string{
+		"unexpected index on characters (name)",
+		"column quotes.scene_id is TEXT, expected INTEGER",
+		"column quotes.scene_id is NULL, expected NOT NULL",
+		"missing column quotes.text",
+		"missing foreign key quotes (scene_id) REFERENCES scenes (id)",
+		"missing table scene_characters",
+		"unexpected table extras",
+	}, diffs)
+}
+
+func TestCheckFields(t *testing.T) {
+	assert := assert.New(t)
+
+	schema, err := introspect.Expected(context.Background())
+	if !assert.NoError(err) {
+		return
+	}
+
+	type actor struct {
+		ID   int64
+		Name string
+	}
+	type character struct {
+		ID      int64
+		ActorID int64
+		Name    string
+
+		Actor  *actor
+		Scenes []int64
+	}
+	assert.Empty(schema.Table("characters").CheckFields(introspect.StructFields(&character{})))
+
+	type quote struct {
+		ID          int64
+		CharacterID string
+		Text        *string
+		Mood        string
+	}
+	fields := introspect.StructFields(quote{})
+	fields = append(fields, introspect.Field{Name: "Where", Column: "scene", Type: fields[0].Type})
+
+	var diffs []string
+	for _, d := range schema.Table("quotes").CheckFields(fields) {
+		diffs = append(diffs, d.String())
+	}
+	assert.Equal([]string{
+		"column quotes.character_id is INTEGER, expected a type for string",
+		"unexpected column quotes.Mood",
+		"unexpected column quotes.scene",
+		"missing column quotes.scene_id",
+	}, diffs)
+}
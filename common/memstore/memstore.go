@@ -0,0 +1,508 @@
+// Package memstore is an in-memory common.CharacterRepository for unit
+// testing code that uses one, without a database.
+//
+// It behaves the same way as the model packages, which is checked by running
+// the repotest conformance suite against it. Actors, scene appearances and
+// quote counts are kept alongside the characters so List can filter and sort
+// on them.
+package memstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pboyd/godbmodels/common"
+)
+
+var _ common.CharacterRepository = (*Store)(nil)
+
+// errUnknownActor is returned when a character's actor doesn't exist. It has
+// the same message as SQLite's error for the foreign key.
+var errUnknownActor = errors.New("FOREIGN KEY constraint failed")
+
+// Store holds characters, actors and scene appearances in maps. It is safe
+// for concurrent use.
+type Store struct {
+	mu sync.RWMutex
+
+	characters map[int64]common.Character
+	actors     map[int64]string
+
+	// scenes maps character IDs to the set of scenes they're in.
+	scenes map[int64]map[int64]struct{}
+
+	// quotes maps character IDs to the number of quotes they have.
+	quotes map[int64]int
+
+	// lastID is the highest character ID that has been used. Like SQLite's
+	// AUTOINCREMENT, IDs aren't reused after a delete.
+	lastID int64
+}
+
+// New returns an empty Store.
+func New() *Store {
+	return &Store{
+		characters: map[int64]common.Character{},
+		actors:     map[int64]string{},
+		scenes:     map[int64]map[int64]struct{}{},
+		quotes:     map[int64]int{},
+	}
+}
+
+// Querier is implemented by *sql.DB, *sql.Tx and *sql.Conn.
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// Load returns a Store with a copy of the actors, characters, scene
+// appearances and quote counts in a database.
+func Load(ctx context.Context, db Querier) (*Store, error) {
+	s := New()
+
+	err := scan(ctx, db, `SELECT id, name FROM actors`, func(rows *sql.Rows) error {
+		var (
+			id   int64
+			name string
+		)
+		err := rows.Scan(&id, &name)
+		if err != nil {
+			return err
+		}
+
+		s.actors[id] = name
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("load actors: %w", err)
+	}
+
+	err = scan(ctx, db, `SELECT id, actor_id, name FROM characters`, func(rows *sql.Rows) error {
+		var c common.Character
+		err := rows.Scan(&c.ID, &c.ActorID, &c.Name)
+		if err != nil {
+			return err
+		}
+
+		s.put(c)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("load characters: %w", err)
+	}
+
+	err = scan(ctx, db, `SELECT scene_id, character_id FROM scene_characters`, func(rows *sql.Rows) error {
+		var sceneID, characterID int64
+		err := rows.Scan(&sceneID, &characterID)
+		if err != nil {
+			return err
+		}
+
+		s.addToScene(sceneID, characterID)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("load scene characters: %w", err)
+	}
+
+	err = scan(ctx, db, `SELECT character_id, COUNT(*) FROM quotes GROUP BY character_id`, func(rows *sql.Rows) error {
+		var (
+			characterID int64
+			n           int
+		)
+		err := rows.Scan(&characterID, &n)
+		if err != nil {
+			return err
+		}
+
+		s.quotes[characterID] = n
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("load quotes: %w", err)
+	}
+
+	return s, nil
+}
+
+func scan(ctx context.Context, db Querier, query string, fn func(*sql.Rows) error) error {
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err := fn(rows)
+		if err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// PutActor adds an actor, or renames an existing one.
+func (s *Store) PutActor(id int64, name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.actors[id] = name
+}
+
+// AddToScene records that a character appears in a scene.
+func (s *Store) AddToScene(sceneID, characterID int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.addToScene(sceneID, characterID)
+}
+
+// AddQuote records that a character has another quote.
+func (s *Store) AddQuote(characterID int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.quotes[characterID]++
+}
+
+func (s *Store) addToScene(sceneID, characterID int64) {
+	if s.scenes[characterID] == nil {
+		s.scenes[characterID] = map[int64]struct{}{}
+	}
+	s.scenes[characterID][sceneID] = struct{}{}
+}
+
+func (s *Store) put(c common.Character) {
+	s.characters[c.ID] = c
+	if c.ID > s.lastID {
+		s.lastID = c.ID
+	}
+}
+
+// Get loads a character by ID.
+//
+// If no character is found, Get returns a nil Character and no error.
+func (s *Store) Get(ctx context.Context, id int64) (*common.Character, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	c, ok := s.characters[id]
+	if !ok {
+		return nil, nil
+	}
+
+	return &c, nil
+}
+
+// Store saves a character. If the character has an ID, it will be updated.
+// Otherwise, it will be inserted and the ID will be set.
+//
+// If the character has an ID and it does not exist, Store returns
+// common.ErrNotFound. The actor must have been loaded or added with PutActor,
+// or Store returns an error, like the foreign key in the database.
+func (s *Store) Store(ctx context.Context, c *common.Character) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.actors[c.ActorID]; !ok {
+		return errUnknownActor
+	}
+
+	if c.ID == 0 {
+		c.ID = s.lastID + 1
+	} else if _, ok := s.characters[c.ID]; !ok {
+		return common.ErrNotFound
+	}
+
+	s.put(*c)
+	return nil
+}
+
+// Delete removes a character, along with its scene appearances.
+//
+// If the character does not exist, Delete returns common.ErrNotFound.
+func (s *Store) Delete(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.characters[id]; !ok {
+		return common.ErrNotFound
+	}
+
+	delete(s.characters, id)
+	delete(s.scenes, id)
+	delete(s.quotes, id)
+	return nil
+}
+
+// List searches for characters.
+//
+// If filters is nil, all characters are returned, in order of ID. Otherwise,
+// the results are filtered and sorted by the criteria in filters.
+func (s *Store) List(ctx context.Context, filters *common.CharacterFilters) ([]*common.Character, error) {
+	return s.list(filters, common.OrderByID)
+}
+
+// ListPage is like List, but returns one page of the results. The default
+// order is by name.
+func (s *Store) ListPage(ctx context.Context, filters *common.CharacterFilters, page common.Page) (*common.CharacterPage, error) {
+	var f common.CharacterFilters
+	if filters != nil {
+		f = *filters
+	}
+
+	after, err := page.After(f.OrderBy.Or(common.OrderByName), f.Descending)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := s.list(filters, common.OrderByName)
+	if err != nil {
+		return nil, err
+	}
+
+	start := page.Offset
+	if after != nil {
+		start = sort.Search(len(list), func(i int) bool {
+			c := list[i]
+			return c.Name > after.Name || (c.Name == after.Name && c.ID > after.ID)
+		})
+	}
+	if start > len(list) {
+		start = len(list)
+	}
+
+	end := start + page.Limit + 1
+	if end > len(list) {
+		end = len(list)
+	}
+
+	result := &common.CharacterPage{}
+	result.Characters, result.HasMore, result.NextCursor = common.TrimPage(list[start:end], page.Limit, cursor)
+	return result, nil
+}
+
+// Count returns the number of characters that List would return for filters.
+func (s *Store) Count(ctx context.Context, filters *common.CharacterFilters) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.filter(filters)), nil
+}
+
+// ListWithFacets is like List, but also counts the matching characters by
+// actor and by scene.
+func (s *Store) ListWithFacets(ctx context.Context, filters *common.CharacterFilters) (*common.CharacterList, error) {
+	list, err := s.list(filters, common.OrderByID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	actors := map[int64]int{}
+	scenes := map[int64]int{}
+	for _, c := range list {
+		actors[c.ActorID]++
+		for sceneID := range s.scenes[c.ID] {
+			scenes[sceneID]++
+		}
+	}
+
+	return &common.CharacterList{
+		Characters: list,
+		Facets: common.Facets{
+			Actors: facetCounts(actors),
+			Scenes: facetCounts(scenes),
+		},
+	}, nil
+}
+
+// facetCounts converts a map of counts to a slice in order of ID.
+func facetCounts(counts map[int64]int) []common.FacetCount {
+	var facets []common.FacetCount
+	for id, n := range counts {
+		facets = append(facets, common.FacetCount{ID: id, Count: n})
+	}
+
+	sort.Slice(facets, func(i, j int) bool {
+		return facets[i].ID < facets[j].ID
+	})
+
+	return facets
+}
+
+func cursor(c *common.Character) common.Cursor {
+	return common.Cursor{Name: c.Name, ID: c.ID}
+}
+
+// list returns the characters that match filters, sorted by filters.OrderBy
+// or def.
+func (s *Store) list(filters *common.CharacterFilters, def common.OrderBy) ([]*common.Character, error) {
+	order, desc := def, false
+	if filters != nil {
+		err := filters.OrderBy.Validate()
+		if err != nil {
+			return nil, err
+		}
+		order, desc = filters.OrderBy.Or(def), filters.Descending
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := s.filter(filters)
+	sort.Slice(list, func(i, j int) bool {
+		n := s.compare(list[i], list[j], order)
+		if n == 0 {
+			n = compare(list[i].ID, list[j].ID)
+		}
+		if desc {
+			return n > 0
+		}
+		return n < 0
+	})
+
+	return list, nil
+}
+
+// filter returns the characters that match filters, in no particular order.
+// The caller must hold s.mu.
+func (s *Store) filter(filters *common.CharacterFilters) []*common.Character {
+	var sets idSets
+	if filters != nil {
+		sets = idSets{
+			actors:        toSet(filters.ActorIDs),
+			excludeActors: toSet(filters.ExcludeActorIDs),
+			scenes:        toSet(filters.SceneNumbers),
+		}
+	}
+
+	var list []*common.Character
+	for _, c := range s.characters {
+		if filters != nil && !s.match(c, filters, &sets) {
+			continue
+		}
+
+		c := c
+		list = append(list, &c)
+	}
+
+	return list
+}
+
+// compare compares two characters by order, the same way the SQL does. Go
+// compares strings byte by byte, like SQLite's default BINARY collation.
+func (s *Store) compare(a, b *common.Character, order common.OrderBy) int {
+	switch order {
+	case common.OrderByName:
+		return strings.Compare(a.Name, b.Name)
+	case common.OrderByActorName:
+		return strings.Compare(s.actors[a.ActorID], s.actors[b.ActorID])
+	case common.OrderBySceneCount:
+		return compare(len(s.scenes[a.ID]), len(s.scenes[b.ID]))
+	case common.OrderByQuoteCount:
+		return compare(s.quotes[a.ID], s.quotes[b.ID])
+	}
+
+	return 0
+}
+
+func compare[T int | int64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// match reports whether c matches the filters the same way the SQL does.
+func (s *Store) match(c common.Character, filters *common.CharacterFilters, sets *idSets) bool {
+	if filters.ActorID != 0 {
+		if c.ActorID != filters.ActorID {
+			return false
+		}
+	} else if filters.ActorName != "" {
+		// The SQL joins on actors, so a character without an actor never
+		// matches.
+		name, ok := s.actors[c.ActorID]
+		if !ok || !containsFold(name, filters.ActorName) {
+			return false
+		}
+	}
+
+	if filters.Name != "" && !containsFold(c.Name, filters.Name) {
+		return false
+	}
+
+	if filters.SceneNumber != 0 {
+		if _, ok := s.scenes[c.ID][filters.SceneNumber]; !ok {
+			return false
+		}
+	}
+
+	if len(sets.actors) > 0 {
+		if _, ok := sets.actors[c.ActorID]; !ok {
+			return false
+		}
+	}
+
+	if _, ok := sets.excludeActors[c.ActorID]; ok {
+		return false
+	}
+
+	if len(sets.scenes) > 0 {
+		n := 0
+		for id := range s.scenes[c.ID] {
+			if _, ok := sets.scenes[id]; ok {
+				n++
+			}
+		}
+		if n == 0 || (filters.AllScenes && n < len(sets.scenes)) {
+			return false
+		}
+	}
+
+	if filters.SceneFrom != 0 || filters.SceneTo != 0 {
+		found := false
+		for id := range s.scenes[c.ID] {
+			if (filters.SceneFrom == 0 || id >= filters.SceneFrom) && (filters.SceneTo == 0 || id <= filters.SceneTo) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// idSets holds the ID lists from a CharacterFilters as sets, so they're
+// built once per List instead of once per character.
+type idSets struct {
+	actors        map[int64]struct{}
+	excludeActors map[int64]struct{}
+	scenes        map[int64]struct{}
+}
+
+func toSet(ids []int64) map[int64]struct{} {
+	set := make(map[int64]struct{}, len(ids))
+	for _, id := range ids {
+		set[id] = struct{}{}
+	}
+	return set
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
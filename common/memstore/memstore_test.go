@@ -0,0 +1,87 @@
+package memstore_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pboyd/godbmodels/common"
+	"github.com/pboyd/godbmodels/common/memstore"
+	"github.com/pboyd/godbmodels/common/repotest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCharacterRepository(t *testing.T) {
+	repotest.TestCharacterRepository(t, func(t testing.TB) common.CharacterRepository {
+		s, err := memstore.Load(context.Background(), common.TestTx(t))
+		if err != nil {
+			t.Fatalf("Error loading store: %s", err)
+		}
+		return s
+	})
+}
+
+func BenchmarkCharacterRepository(b *testing.B) {
+	repotest.BenchmarkCharacterRepository(b, func(t testing.TB) common.CharacterRepository {
+		s, err := memstore.Load(context.Background(), common.TestDB(t))
+		if err != nil {
+			t.Fatalf("Error loading store: %s", err)
+		}
+		return s
+	})
+}
+
+func TestNew(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	s := memstore.New()
+	s.PutActor(1, "Michael Palin")
+	s.PutActor(2, "Neil Innes")
+
+	galahad := &common.Character{ActorID: 1, Name: "Sir Galahad"}
+	if !assert.NoError(s.Store(ctx, galahad)) {
+		return
+	}
+	assert.Equal(int64(1), galahad.ID)
+
+	minstrel := &common.Character{ActorID: 2, Name: "Minstrel"}
+	if !assert.NoError(s.Store(ctx, minstrel)) {
+		return
+	}
+	assert.Equal(int64(2), minstrel.ID)
+	s.AddToScene(12, minstrel.ID)
+
+	list, err := s.List(ctx, &common.CharacterFilters{ActorName: "palin"})
+	if assert.NoError(err) {
+		assert.Equal([]*common.Character{galahad}, list)
+	}
+
+	// Actor 3 was never added, like a missing row in actors.
+	assert.Error(s.Store(ctx, &common.Character{ActorID: 3, Name: "Brother Maynard"}))
+
+	list, err = s.List(ctx, &common.CharacterFilters{SceneNumber: 12})
+	if assert.NoError(err) {
+		assert.Equal([]*common.Character{minstrel}, list)
+	}
+
+	s.AddQuote(galahad.ID)
+	list, err = s.List(ctx, &common.CharacterFilters{OrderBy: common.OrderByQuoteCount, Descending: true})
+	if assert.NoError(err) {
+		assert.Equal([]*common.Character{galahad, minstrel}, list)
+	}
+
+	// IDs aren't reused.
+	assert.NoError(s.Delete(ctx, minstrel.ID))
+	c := &common.Character{ActorID: 1, Name: "Dennis's Mother"}
+	if assert.NoError(s.Store(ctx, c)) {
+		assert.Equal(int64(3), c.ID)
+	}
+
+	// Changing the returned character doesn't change the store.
+	got, err := s.Get(ctx, galahad.ID)
+	if assert.NoError(err) {
+		got.Name = "Sir Lancelot"
+		got, _ = s.Get(ctx, galahad.ID)
+		assert.Equal("Sir Galahad", got.Name)
+	}
+}
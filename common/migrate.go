@@ -0,0 +1,320 @@
+package common
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed migrations/*.sql
+var migrationFS embed.FS
+
+// ErrMissingFeature is returned by Migrate when the database has a migration
+// applied that needs a feature this build of SQLite doesn't have.
+var ErrMissingFeature = errors.New("missing SQLite feature")
+
+// Latest can be passed to Migrate to apply every migration.
+const Latest = math.MaxInt
+
+// Migration is one versioned schema change.
+type Migration struct {
+	Version int
+	Name    string
+
+	// Up applies the migration.
+	Up string
+
+	// Down reverts the migration.
+	Down string
+
+	// Requires lists the optional SQLite features the migration needs, from
+	// "-- requires:" lines in the up file.
+	Requires []string
+}
+
+var (
+	migrationName     = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+	migrationRequires = regexp.MustCompile(`(?m)^-- requires: *(\w+) *$`)
+)
+
+// features maps the names used in "-- requires:" lines to a query that
+// returns true if the database supports the feature.
+var features = map[string]string{
+	"fts5": `SELECT sqlite_compileoption_used('ENABLE_FTS5')`,
+}
+
+// Migrations returns the embedded migrations, ordered by version.
+//
+// Migrations are stored in the migrations directory as pairs of files named
+// NNNN_name.up.sql and NNNN_name.down.sql.
+func Migrations() ([]Migration, error) {
+	files, err := fs.ReadDir(migrationFS, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, f := range files {
+		m := migrationName.FindStringSubmatch(f.Name())
+		if m == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", f.Name())
+		}
+
+		version, _ := strconv.Atoi(m[1])
+		mig := byVersion[version]
+		if mig == nil {
+			mig = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = mig
+		} else if mig.Name != m[2] {
+			return nil, fmt.Errorf("migration %d has two names: %q and %q", version, mig.Name, m[2])
+		}
+
+		body, err := fs.ReadFile(migrationFS, "migrations/"+f.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		if m[3] == "up" {
+			mig.Up = string(body)
+			for _, req := range migrationRequires.FindAllStringSubmatch(mig.Up, -1) {
+				if _, ok := features[req[1]]; !ok {
+					return nil, fmt.Errorf("migration %d requires unknown feature %q", version, req[1])
+				}
+				mig.Requires = append(mig.Requires, req[1])
+			}
+		} else {
+			mig.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" || mig.Down == "" {
+			return nil, fmt.Errorf("migration %d (%s) must have both up and down files", mig.Version, mig.Name)
+		}
+		migrations = append(migrations, *mig)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Version returns the highest migration version applied to the database, or
+// 0 if none have been applied.
+func Version(ctx context.Context, db *sql.DB) (int, error) {
+	applied, err := appliedMigrations(ctx, db)
+	if err != nil {
+		return 0, err
+	}
+
+	version := 0
+	for v := range applied {
+		if v > version {
+			version = v
+		}
+	}
+
+	return version, nil
+}
+
+// Supported reports whether db has every feature the migration requires.
+func (m Migration) Supported(ctx context.Context, db *sql.DB) (bool, error) {
+	for _, req := range m.Requires {
+		var ok bool
+		err := db.QueryRowContext(ctx, features[req]).Scan(&ok)
+		if err != nil {
+			return false, fmt.Errorf("check for %s: %w", req, err)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// Migrate applies every migration up to and including target that hasn't
+// been applied yet. Pass Latest to bring the database fully up to date.
+//
+// Migrations that need a feature the database doesn't support are skipped
+// and not recorded, so they are applied by a later Migrate with a build
+// that supports them.
+//
+// If a migration that needs a missing feature was already applied by
+// another build, the error wraps ErrMissingFeature. Writes to the database
+// would fail anyway, since the migration's triggers need the feature too.
+//
+// Each migration runs in its own transaction, so if one fails the database
+// is left at the last version that succeeded.
+func Migrate(ctx context.Context, db *sql.DB, target int) error {
+	migrations, err := Migrations()
+	if err != nil {
+		return err
+	}
+
+	applied, err := appliedMigrations(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	for _, mig := range migrations {
+		if mig.Version > target {
+			break
+		}
+
+		ok, err := mig.Supported(ctx, db)
+		if err != nil {
+			return fmt.Errorf("migration %d (%s): %w", mig.Version, mig.Name, err)
+		}
+		if applied[mig.Version] {
+			if !ok {
+				return fmt.Errorf("%w: migration %d (%s) was applied with %s, which this build doesn't have (build with -tags sqlite_fts5)",
+					ErrMissingFeature, mig.Version, mig.Name, strings.Join(mig.Requires, ", "))
+			}
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		err = runMigration(ctx, db, mig.Up, `INSERT INTO schema_migrations (version, name) VALUES (?, ?)`, mig.Version, mig.Name)
+		if err != nil {
+			return fmt.Errorf("migration %d (%s): %w", mig.Version, mig.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// Rollback reverts every applied migration with a version greater than
+// target, newest first. Rollback to 0 removes the entire schema.
+func Rollback(ctx context.Context, db *sql.DB, target int) error {
+	migrations, err := Migrations()
+	if err != nil {
+		return err
+	}
+
+	applied, err := appliedMigrations(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	known := make(map[int]bool, len(migrations))
+	for _, mig := range migrations {
+		known[mig.Version] = true
+	}
+	for v := range applied {
+		if v > target && !known[v] {
+			return fmt.Errorf("migration %d is applied but unknown", v)
+		}
+	}
+
+	for i := len(migrations) - 1; i >= 0; i-- {
+		mig := migrations[i]
+		if mig.Version <= target {
+			break
+		}
+		if !applied[mig.Version] {
+			continue
+		}
+
+		err := runMigration(ctx, db, mig.Down, `DELETE FROM schema_migrations WHERE version = ?`, mig.Version)
+		if err != nil {
+			return fmt.Errorf("rollback %d (%s): %w", mig.Version, mig.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func runMigration(ctx context.Context, db *sql.DB, body, bookkeeping string, args ...interface{}) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, body)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, bookkeeping, args...)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// appliedMigrations returns the set of migration versions recorded in the
+// schema_migrations table, creating the table if necessary.
+//
+// Databases created before migrations were introduced have the initial schema
+// but no schema_migrations table. Those are recorded as being at version 1.
+func appliedMigrations(ctx context.Context, db *sql.DB) (map[int]bool, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var hasMigrations, hasSchema bool
+	err = tx.QueryRowContext(ctx, `SELECT
+		EXISTS (SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'),
+		EXISTS (SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'actors')`).
+		Scan(&hasMigrations, &hasSchema)
+	if err != nil {
+		return nil, fmt.Errorf("check schema: %w", err)
+	}
+
+	if !hasMigrations {
+		_, err = tx.ExecContext(ctx, `CREATE TABLE schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`)
+		if err != nil {
+			return nil, fmt.Errorf("create schema_migrations: %w", err)
+		}
+
+		if hasSchema {
+			_, err = tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, name) VALUES (1, 'initial')`)
+			if err != nil {
+				return nil, fmt.Errorf("record initial schema: %w", err)
+			}
+		}
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT version FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("list migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := map[int]bool{}
+	for rows.Next() {
+		var v int
+		err := rows.Scan(&v)
+		if err != nil {
+			return nil, fmt.Errorf("list migrations: %w", err)
+		}
+		applied[v] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list migrations: %w", err)
+	}
+	rows.Close()
+
+	return applied, tx.Commit()
+}
@@ -0,0 +1,267 @@
+package common
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrations(t *testing.T) {
+	assert := assert.New(t)
+
+	migrations, err := Migrations()
+	if !assert.NoError(err) {
+		return
+	}
+
+	if assert.NotEmpty(migrations) {
+		assert.Equal(1, migrations[0].Version)
+		assert.Equal("initial", migrations[0].Name)
+	}
+	for i := 1; i < len(migrations); i++ {
+		assert.Less(migrations[i-1].Version, migrations[i].Version)
+	}
+}
+
+func TestMigrateRollback(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	db, err := sql.Open(DriverName, DSN(":memory:"))
+	if !assert.NoError(err) {
+		return
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	latest := latestSupported(t, db)
+
+	// Partial
+	err = Migrate(ctx, db, 1)
+	if !assert.NoError(err) {
+		return
+	}
+	assertVersion(t, db, 1)
+	assert.Equal([]string{"actors", "characters", "quotes", "scene_characters", "scenes"}, tableNames(t, db))
+
+	// Everything, twice
+	for i := 0; i < 2; i++ {
+		err = Migrate(ctx, db, Latest)
+		if !assert.NoError(err) {
+			return
+		}
+		assertVersion(t, db, latest)
+	}
+	assert.NoError(Populate(db))
+
+	// Down to nothing
+	err = Rollback(ctx, db, 0)
+	if !assert.NoError(err) {
+		return
+	}
+	assertVersion(t, db, 0)
+	assert.Empty(tableNames(t, db))
+
+	// And back up
+	err = Migrate(ctx, db, Latest)
+	if !assert.NoError(err) {
+		return
+	}
+	assertVersion(t, db, latest)
+}
+
+func TestOpenExisting(t *testing.T) {
+	assert := assert.New(t)
+	dbPath := filepath.Join(t.TempDir(), "grail.db")
+
+	// Create a database the way Open used to, with the initial schema and
+	// no migration bookkeeping.
+	migrations, err := Migrations()
+	if !assert.NoError(err) {
+		return
+	}
+	db, err := sql.Open(DriverName, DSN(dbPath))
+	if !assert.NoError(err) {
+		return
+	}
+	_, err = db.Exec(migrations[0].Up)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.NoError(Populate(db))
+	db.Close()
+
+	// Opening it more than once must not fail.
+	for i := 0; i < 2; i++ {
+		db, err := Open(dbPath)
+		if !assert.NoError(err) {
+			return
+		}
+		assertVersion(t, db, latestSupported(t, db))
+
+		var count int
+		err = db.QueryRow(`SELECT COUNT(*) FROM characters`).Scan(&count)
+		assert.NoError(err)
+		assert.Equal(81, count)
+		db.Close()
+	}
+}
+
+func TestQuoteSearchMigration(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	db := TestDB(t)
+
+	migrations, err := Migrations()
+	if !assert.NoError(err) {
+		return
+	}
+
+	var search Migration
+	for _, mig := range migrations {
+		if mig.Name == "quote_search" {
+			search = mig
+		}
+	}
+	assert.Equal([]string{"fts5"}, search.Requires)
+
+	ok, err := search.Supported(ctx, db)
+	if !assert.NoError(err) {
+		return
+	}
+	if !ok {
+		// The migration was skipped, and not recorded.
+		var n int
+		err = db.QueryRow(`SELECT COUNT(*) FROM schema_migrations WHERE version = ?`, search.Version).Scan(&n)
+		if assert.NoError(err) {
+			assert.Zero(n)
+		}
+		assert.NotContains(tableNames(t, db), "quotes_fts")
+		t.Skip("SQLite was built without FTS5")
+	}
+
+	match := func(query string) []int64 {
+		rows, err := db.Query(`SELECT rowid FROM quotes_fts WHERE quotes_fts MATCH ? ORDER BY rowid`, query)
+		if err != nil {
+			t.Fatalf("Error searching quotes: %s", err)
+		}
+		defer rows.Close()
+
+		ids := []int64{}
+		for rows.Next() {
+			var id int64
+			if err := rows.Scan(&id); err != nil {
+				t.Fatalf("Error searching quotes: %s", err)
+			}
+			ids = append(ids, id)
+		}
+		return ids
+	}
+
+	// Existing quotes are indexed.
+	assert.Equal([]int64{12}, match("model"))
+
+	// Insert
+	res, err := db.Exec(`INSERT INTO quotes (character_id, scene_id, text) VALUES (20, 6, 'Oh, it''s only a shrubbery.')`)
+	if !assert.NoError(err) {
+		return
+	}
+	id, _ := res.LastInsertId()
+	assert.Equal([]int64{id}, match("shrubbery"))
+
+	// Update
+	_, err = db.Exec(`UPDATE quotes SET text = 'Clop clop clop.' WHERE id = ?`, id)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Empty(match("shrubbery"))
+	assert.Equal([]int64{id}, match("clop"))
+
+	// Delete
+	_, err = db.Exec(`DELETE FROM quotes WHERE id = ?`, id)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Empty(match("clop"))
+}
+
+func TestMigrateMissingFeature(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	db := TestDB(t)
+
+	// Pretend another build applied every migration, then take away FTS5.
+	migrations, err := Migrations()
+	if !assert.NoError(err) {
+		return
+	}
+	for _, mig := range migrations {
+		_, err := db.Exec(`INSERT OR IGNORE INTO schema_migrations (version, name) VALUES (?, ?)`, mig.Version, mig.Name)
+		if !assert.NoError(err) {
+			return
+		}
+	}
+
+	fts5 := features["fts5"]
+	features["fts5"] = `SELECT 0`
+	t.Cleanup(func() { features["fts5"] = fts5 })
+
+	err = Migrate(ctx, db, Latest)
+	assert.True(errors.Is(err, ErrMissingFeature), "%v", err)
+}
+
+// latestSupported returns the highest version of the migrations db supports.
+func latestSupported(t *testing.T, db *sql.DB) int {
+	t.Helper()
+
+	migrations, err := Migrations()
+	if err != nil {
+		t.Fatalf("Error loading migrations: %s", err)
+	}
+
+	latest := 0
+	for _, mig := range migrations {
+		ok, err := mig.Supported(context.Background(), db)
+		if err != nil {
+			t.Fatalf("Error checking migration %d: %s", mig.Version, err)
+		}
+		if ok {
+			latest = mig.Version
+		}
+	}
+	return latest
+}
+
+func assertVersion(t *testing.T, db *sql.DB, expected int) {
+	t.Helper()
+
+	version, err := Version(context.Background(), db)
+	if assert.NoError(t, err) {
+		assert.Equal(t, expected, version)
+	}
+}
+
+func tableNames(t *testing.T, db *sql.DB) []string {
+	t.Helper()
+
+	rows, err := db.Query(`SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT IN ('schema_migrations', 'sqlite_sequence') ORDER BY name`)
+	if err != nil {
+		t.Fatalf("Error listing tables: %s", err)
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			t.Fatalf("Error listing tables: %s", err)
+		}
+		names = append(names, name)
+	}
+
+	return names
+}
@@ -0,0 +1,5 @@
+DROP TABLE quotes;
+DROP TABLE scene_characters;
+DROP TABLE scenes;
+DROP TABLE characters;
+DROP TABLE actors;
@@ -0,0 +1,26 @@
+-- requires: fts5
+--
+-- Full-text index over quotes. The index reads the text from the quotes
+-- table (content='quotes'), so it only stores the tokens, and the triggers
+-- keep it in sync.
+
+CREATE VIRTUAL TABLE quotes_fts USING fts5 (
+    text,
+    content = 'quotes',
+    content_rowid = 'id'
+);
+
+CREATE TRIGGER quotes_fts_insert AFTER INSERT ON quotes BEGIN
+    INSERT INTO quotes_fts (rowid, text) VALUES (new.id, new.text);
+END;
+
+CREATE TRIGGER quotes_fts_delete AFTER DELETE ON quotes BEGIN
+    INSERT INTO quotes_fts (quotes_fts, rowid, text) VALUES ('delete', old.id, old.text);
+END;
+
+CREATE TRIGGER quotes_fts_update AFTER UPDATE OF text ON quotes BEGIN
+    INSERT INTO quotes_fts (quotes_fts, rowid, text) VALUES ('delete', old.id, old.text);
+    INSERT INTO quotes_fts (rowid, text) VALUES (new.id, new.text);
+END;
+
+INSERT INTO quotes_fts (quotes_fts) VALUES ('rebuild');
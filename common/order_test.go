@@ -0,0 +1,50 @@
+package common
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderClause(t *testing.T) {
+	assert := assert.New(t)
+
+	clause, err := OrderClause("c", OrderByID, false)
+	if assert.NoError(err) {
+		assert.Equal([]string{"c.id"}, clause)
+	}
+
+	clause, err = OrderClause("characters", OrderBySceneCount, true)
+	if assert.NoError(err) {
+		assert.Equal([]string{
+			"(SELECT COUNT(*) FROM scene_characters osc WHERE osc.character_id = characters.id) DESC",
+			"characters.id DESC",
+		}, clause)
+	}
+
+	for _, o := range []OrderBy{"", "name; DROP TABLE characters", "NAME"} {
+		_, err = OrderClause("c", o, false)
+		assert.True(errors.Is(err, ErrInvalidOrder), "%q: %v", o, err)
+	}
+}
+
+func TestOrderBys(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Len(OrderBys, len(orderExprs))
+	for _, o := range OrderBys {
+		assert.NoError(o.Validate())
+	}
+}
+
+func TestOrderByValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(OrderBy("").Validate())
+	assert.NoError(OrderByActorName.Validate())
+	assert.True(errors.Is(OrderBy("actor").Validate(), ErrInvalidOrder))
+
+	assert.Equal(OrderByName, OrderBy("").Or(OrderByName))
+	assert.Equal(OrderByID, OrderByID.Or(OrderByName))
+}
@@ -0,0 +1,119 @@
+package common
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrInvalidPage is returned when a Page's fields don't make sense
+	// together.
+	ErrInvalidPage = errors.New("invalid page")
+
+	// ErrInvalidCursor is returned when a Page's Cursor wasn't made by
+	// EncodeCursor.
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// Page selects one page of List results. Pages are in order of name, and
+// then ID for characters with the same name, unless CharacterFilters.OrderBy
+// says otherwise.
+//
+// A page starts either Offset characters in, or after the character in
+// Cursor. Cursors come from the NextCursor of the previous page. Unlike
+// offsets, they don't skip or repeat characters when others are added or
+// removed between pages, and the database doesn't read the skipped rows.
+type Page struct {
+	// Limit is the most characters to return. It must be above zero.
+	Limit int
+
+	// Offset is the number of characters to skip. It can't be used with
+	// Cursor.
+	Offset int
+
+	// Cursor continues from the end of a previous page. Cursors only work
+	// when the pages are in ascending order of name.
+	Cursor string
+}
+
+// After checks the page and returns the position it starts after, or nil if
+// it doesn't have a Cursor. o and desc are the order the pages are in.
+//
+// The error wraps ErrInvalidPage or ErrInvalidCursor.
+func (p Page) After(o OrderBy, desc bool) (*Cursor, error) {
+	switch {
+	case p.Limit <= 0:
+		return nil, fmt.Errorf("%w: limit must be above zero", ErrInvalidPage)
+	case p.Offset < 0:
+		return nil, fmt.Errorf("%w: offset can't be negative", ErrInvalidPage)
+	case p.Offset > 0 && p.Cursor != "":
+		return nil, fmt.Errorf("%w: offset and cursor can't be used together", ErrInvalidPage)
+	case p.Cursor == "":
+		return nil, nil
+	case o != OrderByName || desc:
+		return nil, fmt.Errorf("%w: cursors only work in ascending order of name", ErrInvalidPage)
+	}
+
+	c, err := DecodeCursor(p.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// Cursor is the position of a character in name, ID order.
+type Cursor struct {
+	Name string `json:"n"`
+	ID   int64  `json:"i"`
+}
+
+// EncodeCursor returns an opaque string for a cursor, to be passed back in
+// Page.Cursor.
+func EncodeCursor(c Cursor) string {
+	// Marshaling a string and an int can't fail.
+	buf, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+// DecodeCursor reverses EncodeCursor. The error wraps ErrInvalidCursor.
+func DecodeCursor(s string) (Cursor, error) {
+	var c Cursor
+
+	buf, err := base64.RawURLEncoding.DecodeString(s)
+	if err == nil {
+		err = json.Unmarshal(buf, &c)
+	}
+	if err != nil || c.ID <= 0 {
+		return Cursor{}, fmt.Errorf("%w %q", ErrInvalidCursor, s)
+	}
+
+	return c, nil
+}
+
+// TrimPage finishes a page that was queried with a limit of one more than
+// limit. It trims list to limit and reports whether there was anything more,
+// along with the cursor for the next page, which is found by calling cursor
+// on the last item.
+func TrimPage[T any](list []T, limit int, cursor func(T) Cursor) ([]T, bool, string) {
+	if len(list) <= limit {
+		return list, false, ""
+	}
+
+	list = list[:limit]
+	return list, true, EncodeCursor(cursor(list[limit-1]))
+}
+
+// CharacterPage is one page of characters from List.
+type CharacterPage struct {
+	Characters []*Character
+
+	// HasMore is true if there are characters after this page.
+	HasMore bool
+
+	// NextCursor continues from the last character on this page. It's empty
+	// if HasMore is false.
+	NextCursor string
+}
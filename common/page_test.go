@@ -0,0 +1,78 @@
+package common
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor(t *testing.T) {
+	assert := assert.New(t)
+
+	c := Cursor{Name: "Brother Maynard's Brother", ID: 47}
+	s := EncodeCursor(c)
+	assert.NotContains(s, "Maynard")
+
+	c2, err := DecodeCursor(s)
+	if assert.NoError(err) {
+		assert.Equal(c, c2)
+	}
+
+	for _, bad := range []string{"", "not a cursor", EncodeCursor(Cursor{Name: "Tim"})} {
+		_, err := DecodeCursor(bad)
+		assert.True(errors.Is(err, ErrInvalidCursor), "%q: %v", bad, err)
+	}
+}
+
+func TestPageAfter(t *testing.T) {
+	cursor := EncodeCursor(Cursor{Name: "Patsy", ID: 2})
+
+	cases := map[string]struct {
+		page     Page
+		order    OrderBy
+		desc     bool
+		expected *Cursor
+		err      error
+	}{
+		"First":       {page: Page{Limit: 10}},
+		"Offset":      {page: Page{Limit: 10, Offset: 20}},
+		"Cursor":      {page: Page{Limit: 10, Cursor: cursor}, expected: &Cursor{Name: "Patsy", ID: 2}},
+		"NoLimit":     {page: Page{}, err: ErrInvalidPage},
+		"Negative":    {page: Page{Limit: 10, Offset: -1}, err: ErrInvalidPage},
+		"Both":        {page: Page{Limit: 10, Offset: 20, Cursor: cursor}, err: ErrInvalidPage},
+		"WrongCursor": {page: Page{Limit: 10, Cursor: "Patsy"}, err: ErrInvalidCursor},
+		"OffsetOrder": {page: Page{Limit: 10, Offset: 20}, order: OrderBySceneCount, desc: true},
+		"CursorOrder": {page: Page{Limit: 10, Cursor: cursor}, order: OrderByID, err: ErrInvalidPage},
+		"CursorDesc":  {page: Page{Limit: 10, Cursor: cursor}, desc: true, err: ErrInvalidPage},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			after, err := tc.page.After(tc.order.Or(OrderByName), tc.desc)
+			if tc.err != nil {
+				assert.True(t, errors.Is(err, tc.err), "%v", err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.expected, after)
+			}
+		})
+	}
+}
+
+func TestTrimPage(t *testing.T) {
+	assert := assert.New(t)
+	cursor := func(c *Character) Cursor { return Cursor{Name: c.Name, ID: c.ID} }
+	list := []*Character{{ID: 3, Name: "Dennis"}, {ID: 1, Name: "King Arthur"}, {ID: 2, Name: "Patsy"}}
+
+	page, more, next := TrimPage(list, 3, cursor)
+	assert.Len(page, 3)
+	assert.False(more)
+	assert.Empty(next)
+
+	page, more, next = TrimPage(list, 2, cursor)
+	assert.Equal(list[:2], page)
+	assert.True(more)
+	assert.Equal(EncodeCursor(Cursor{Name: "King Arthur", ID: 1}), next)
+}
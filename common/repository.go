@@ -0,0 +1,107 @@
+package common
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound is returned by a CharacterRepository when updating or deleting
+// a character that does not exist in the database.
+var ErrNotFound = errors.New("not found")
+
+// Character is one character from the database.
+type Character struct {
+	ID      int64
+	ActorID int64
+	Name    string
+}
+
+// CharacterFilters are used to filter the results of a List query.
+type CharacterFilters struct {
+	// ActorID matches on the actor's ID.
+	ActorID int64
+
+	// ActorName does a case-insensitive partial match on the actor name.
+	ActorName string
+
+	// Name does a case-insensitive partial match on the character name.
+	Name string
+
+	// SceneNumber filters by the scene that the character appears in.
+	SceneNumber int64
+
+	// ActorIDs matches characters played by any of these actors.
+	ActorIDs []int64
+
+	// ExcludeActorIDs leaves out characters played by any of these actors.
+	ExcludeActorIDs []int64
+
+	// SceneNumbers matches characters that appear in any of these scenes,
+	// or in every one of them if AllScenes is set.
+	SceneNumbers []int64
+
+	// AllScenes makes SceneNumbers match characters that appear in every
+	// listed scene.
+	AllScenes bool
+
+	// SceneFrom and SceneTo match characters that appear in at least one
+	// scene in the range, inclusive. Either can be zero to leave that end
+	// of the range open.
+	SceneFrom int64
+	SceneTo   int64
+
+	// OrderBy sorts the results. List defaults to OrderByID and ListPage
+	// defaults to OrderByName.
+	OrderBy OrderBy
+
+	// Descending reverses the order.
+	Descending bool
+}
+
+// CharacterRepository is the set of character operations that every model
+// package supports. Each package provides an adapter from its own API to this
+// interface so they can be tested and benchmarked the same way.
+type CharacterRepository interface {
+	// Get loads a character from the database by ID.
+	//
+	// If no character is found, Get returns a nil Character and no error.
+	Get(ctx context.Context, id int64) (*Character, error)
+
+	// Store saves a character to the database. If the character has an ID,
+	// it will be updated. Otherwise, it will be inserted and the ID will be
+	// set.
+	//
+	// If the character has an ID and it does not exist in the database,
+	// Store returns ErrNotFound.
+	Store(ctx context.Context, c *Character) error
+
+	// Delete removes a character from the database.
+	//
+	// If the character does not exist in the database, Delete returns
+	// ErrNotFound.
+	Delete(ctx context.Context, id int64) error
+
+	// List searches for characters in the database.
+	//
+	// If filters is nil, all characters are returned, in order of ID.
+	// Otherwise, the results are filtered and sorted by the criteria in
+	// filters. If filters.OrderBy is invalid, the error wraps
+	// ErrInvalidOrder.
+	List(ctx context.Context, filters *CharacterFilters) ([]*Character, error)
+
+	// ListPage is like List, but returns one page of the results. The
+	// default order is by name.
+	//
+	// If page is invalid, the error wraps ErrInvalidPage or
+	// ErrInvalidCursor.
+	ListPage(ctx context.Context, filters *CharacterFilters, page Page) (*CharacterPage, error)
+
+	// Count returns the number of characters that List would return for
+	// filters. filters.OrderBy and Descending are ignored.
+	Count(ctx context.Context, filters *CharacterFilters) (int, error)
+
+	// ListWithFacets is like List, but also counts the matching characters
+	// by actor and by scene. The counts are done by the database, not by
+	// looking through the list.
+	ListWithFacets(ctx context.Context, filters *CharacterFilters) (*CharacterList, error)
+}
@@ -0,0 +1,194 @@
+package repotest
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/pboyd/godbmodels/common"
+)
+
+// BenchmarkCharacterRepository benchmarks every CharacterRepository operation
+// against the repository returned by open. Each sub-benchmark gets a fresh
+// repository loaded with the standard test data, so the results from
+// different packages are comparable.
+func BenchmarkCharacterRepository(b *testing.B, open OpenFunc) {
+	b.Run("Get", func(b *testing.B) { benchmarkGet(b, open(b)) })
+	b.Run("Insert", func(b *testing.B) { benchmarkInsert(b, open(b)) })
+	b.Run("Update", func(b *testing.B) { benchmarkUpdate(b, open(b)) })
+	b.Run("Delete", func(b *testing.B) { benchmarkDelete(b, open(b)) })
+
+	for _, f := range filterCombinations() {
+		f := f
+		b.Run("List/"+filterName(f), func(b *testing.B) { benchmarkList(b, open(b), f) })
+	}
+
+	b.Run("ListPage/Offset", func(b *testing.B) { benchmarkListPage(b, open(b), false) })
+	b.Run("ListPage/Cursor", func(b *testing.B) { benchmarkListPage(b, open(b), true) })
+}
+
+func benchmarkGet(b *testing.B, repo common.CharacterRepository) {
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, err := repo.Get(ctx, int64(i%81)+1)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkInsert(b *testing.B, repo common.CharacterRepository) {
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		c := common.Character{
+			ActorID: 1,
+			Name:    "Sir Not-Appearing-in-this-Film",
+		}
+		err := repo.Store(ctx, &c)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkUpdate(b *testing.B, repo common.CharacterRepository) {
+	ctx := context.Background()
+	names := []string{"King Arthur", "Arthur, King of the Britons"}
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		c := common.Character{
+			ID:      1,
+			ActorID: 1,
+			Name:    names[i%2],
+		}
+		err := repo.Store(ctx, &c)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkDelete(b *testing.B, repo common.CharacterRepository) {
+	ctx := context.Background()
+
+	ids := make([]int64, b.N)
+	for i := range ids {
+		c := common.Character{
+			ActorID: 1,
+			Name:    "Sir Not-Appearing-in-this-Film",
+		}
+		err := repo.Store(ctx, &c)
+		if err != nil {
+			b.Fatal(err)
+		}
+		ids[i] = c.ID
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for _, id := range ids {
+		err := repo.Delete(ctx, id)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkList(b *testing.B, repo common.CharacterRepository, filters *common.CharacterFilters) {
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, err := repo.List(ctx, filters)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// benchmarkListPage pages through every character, 20 at a time, for each
+// iteration.
+func benchmarkListPage(b *testing.B, repo common.CharacterRepository, cursor bool) {
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		page := common.Page{Limit: 20}
+		for {
+			p, err := repo.ListPage(ctx, nil, page)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if !p.HasMore {
+				break
+			}
+
+			if cursor {
+				page.Cursor = p.NextCursor
+			} else {
+				page.Offset += page.Limit
+			}
+		}
+	}
+}
+
+// filterCombinations returns filters with every combination of the ActorID,
+// ActorName, Name and SceneNumber fields set.
+//
+// The values are chosen so every combination matches at least one
+// character: Michael Palin plays the Narrator, who is in scene 5.
+func filterCombinations() []*common.CharacterFilters {
+	var combinations []*common.CharacterFilters
+
+	for mask := 0; mask < 16; mask++ {
+		f := &common.CharacterFilters{}
+		if mask&1 != 0 {
+			f.ActorID = 6
+		}
+		if mask&2 != 0 {
+			f.ActorName = "palin"
+		}
+		if mask&4 != 0 {
+			f.Name = "narrator"
+		}
+		if mask&8 != 0 {
+			f.SceneNumber = 5
+		}
+		combinations = append(combinations, f)
+	}
+
+	return combinations
+}
+
+func filterName(f *common.CharacterFilters) string {
+	var fields []string
+	if f.ActorID != 0 {
+		fields = append(fields, "ActorID")
+	}
+	if f.ActorName != "" {
+		fields = append(fields, "ActorName")
+	}
+	if f.Name != "" {
+		fields = append(fields, "Name")
+	}
+	if f.SceneNumber != 0 {
+		fields = append(fields, "SceneNumber")
+	}
+
+	if len(fields) == 0 {
+		return "None"
+	}
+
+	return strings.Join(fields, "+")
+}
@@ -0,0 +1,120 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pboyd/godbmodels/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// Include selects the relations a DetailFunc loads.
+type Include struct {
+	Actor  bool
+	Scenes bool
+	Quotes bool
+}
+
+// Detail is a character with the relations from a package's GetDetail,
+// copied into types the suite can compare. Relations that weren't included
+// must be left nil, and included relations with no rows must be empty but
+// not nil.
+type Detail struct {
+	Character common.Character
+	Actor     *Actor
+	Scenes    []Scene
+	Quotes    []Quote
+}
+
+// Actor is an actor in a Detail.
+type Actor struct {
+	ID   int64
+	Name string
+}
+
+// Scene is a scene in a Detail.
+type Scene struct {
+	ID   int64
+	Name string
+}
+
+// Quote is a quote in a Detail.
+type Quote struct {
+	ID          int64
+	CharacterID int64
+}
+
+// DetailFunc loads a character and the relations selected by include. If the
+// character does not exist, it returns a nil Detail and no error.
+type DetailFunc func(ctx context.Context, id int64, include Include) (*Detail, error)
+
+// OpenDetailFunc returns a DetailFunc for a single test. Like OpenFunc, it
+// must be backed by a database populated with the standard test data.
+type OpenDetailFunc func(t testing.TB) DetailFunc
+
+// TestGetDetail runs the GetDetail suite against the DetailFunc returned by
+// open. Each subtest calls open to get a fresh one.
+func TestGetDetail(t *testing.T, open OpenDetailFunc) {
+	all := Include{Actor: true, Scenes: true, Quotes: true}
+	graham := &Actor{ID: 1, Name: "Graham Chapman"}
+
+	cases := map[string]struct {
+		include Include
+		actor   *Actor
+		scenes  int
+		quotes  []int64
+	}{
+		"None":   {},
+		"Actor":  {include: Include{Actor: true}, actor: graham},
+		"Scenes": {include: Include{Scenes: true}, scenes: 16},
+		"Quotes": {include: Include{Quotes: true}, quotes: []int64{1, 5, 9}},
+		"All":    {include: all, actor: graham, scenes: 16, quotes: []int64{1, 5, 9}},
+	}
+
+	for name, tc := range cases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			// King Arthur
+			d, err := open(t)(context.Background(), 1, tc.include)
+			if !assert.NoError(err) || !assert.NotNil(d) {
+				return
+			}
+			assert.Equal(common.Character{ID: 1, ActorID: 1, Name: "King Arthur"}, d.Character)
+			assert.Equal(tc.actor, d.Actor)
+
+			if !tc.include.Scenes {
+				assert.Nil(d.Scenes)
+			} else if assert.Len(d.Scenes, tc.scenes) {
+				assert.Equal(Scene{ID: 1, Name: "The Trouble With Swallows"}, d.Scenes[0])
+				assert.Equal(int64(23), d.Scenes[tc.scenes-1].ID)
+			}
+
+			var quotes []int64
+			for _, q := range d.Quotes {
+				assert.Equal(int64(1), q.CharacterID)
+				quotes = append(quotes, q.ID)
+			}
+			assert.Equal(tc.quotes, quotes)
+		})
+	}
+
+	t.Run("Missing", func(t *testing.T) {
+		d, err := open(t)(context.Background(), 9999, all)
+		if assert.NoError(t, err) {
+			assert.Nil(t, d)
+		}
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		assert := assert.New(t)
+
+		// Brother Maynard's Brother has no quotes.
+		d, err := open(t)(context.Background(), 41, Include{Quotes: true})
+		if assert.NoError(err) && assert.NotNil(d) {
+			assert.NotNil(d.Quotes)
+			assert.Empty(d.Quotes)
+		}
+	})
+}
@@ -0,0 +1,735 @@
+// Package repotest implements a conformance test suite for
+// common.CharacterRepository implementations.
+//
+// Every model package runs the same suite against its adapter, so any
+// difference in behavior between the packages shows up as a test failure.
+package repotest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/pboyd/godbmodels/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// OpenFunc returns a CharacterRepository for a single test. The repository
+// must be backed by a database populated with the standard test data (see
+// common.TestDB).
+type OpenFunc func(t testing.TB) common.CharacterRepository
+
+// TestCharacterRepository runs the conformance suite against the repository
+// returned by open. Each subtest calls open to get a fresh repository.
+func TestCharacterRepository(t *testing.T, open OpenFunc) {
+	t.Run("CRUD", func(t *testing.T) { testCRUD(t, open(t)) })
+	t.Run("GetMissing", func(t *testing.T) { testGetMissing(t, open(t)) })
+	t.Run("UpdateMissing", func(t *testing.T) { testUpdateMissing(t, open(t)) })
+	t.Run("UnknownActor", func(t *testing.T) { testUnknownActor(t, open(t)) })
+	t.Run("DeleteMissing", func(t *testing.T) { testDeleteMissing(t, open(t)) })
+	t.Run("List", func(t *testing.T) { testList(t, open(t)) })
+	t.Run("ListSets", func(t *testing.T) { testListSets(t, open(t)) })
+	t.Run("ListOrder", func(t *testing.T) { testListOrder(t, open(t)) })
+	t.Run("ListPage", func(t *testing.T) { testListPage(t, open(t)) })
+	t.Run("ListPageInvalid", func(t *testing.T) { testListPageInvalid(t, open(t)) })
+	t.Run("Count", func(t *testing.T) { testCount(t, open(t)) })
+	t.Run("ListWithFacets", func(t *testing.T) { testListWithFacets(t, open(t)) })
+}
+
+func testCRUD(t *testing.T, repo common.CharacterRepository) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	c := &common.Character{
+		Name:    "Sir Not-Appearing-in-this-Film",
+		ActorID: 1,
+	}
+
+	// Create
+	err := repo.Store(ctx, c)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.NotZero(c.ID)
+
+	// Read
+	c2, err := repo.Get(ctx, c.ID)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Equal(c, c2)
+
+	// Update
+	c.Name = "Sir Maybe-Appearing-in-this-Film"
+	c.ActorID = 2
+	err = repo.Store(ctx, c)
+	if !assert.NoError(err) {
+		return
+	}
+	c2, err = repo.Get(ctx, c.ID)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Equal(c, c2)
+
+	// Delete
+	err = repo.Delete(ctx, c.ID)
+	if !assert.NoError(err) {
+		return
+	}
+	c2, err = repo.Get(ctx, c.ID)
+	if assert.NoError(err) {
+		assert.Nil(c2)
+	}
+
+	// Delete again
+	err = repo.Delete(ctx, c.ID)
+	assert.ErrorIs(err, common.ErrNotFound)
+}
+
+func testGetMissing(t *testing.T, repo common.CharacterRepository) {
+	c, err := repo.Get(context.Background(), 9999)
+	if assert.NoError(t, err) {
+		assert.Nil(t, c)
+	}
+}
+
+func testUpdateMissing(t *testing.T, repo common.CharacterRepository) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	c := &common.Character{
+		ID:      9999,
+		Name:    "Sir Not-Appearing-in-this-Film",
+		ActorID: 1,
+	}
+	err := repo.Store(ctx, c)
+	assert.ErrorIs(err, common.ErrNotFound)
+
+	// The failed update must not have inserted anything.
+	c2, err := repo.Get(ctx, c.ID)
+	if assert.NoError(err) {
+		assert.Nil(c2)
+	}
+}
+
+// testUnknownActor checks that characters can't be stored with an actor that
+// doesn't exist. The SQL stores get SQLite's foreign key error, and memstore
+// returns the same message.
+func testUnknownActor(t *testing.T, repo common.CharacterRepository) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	// Insert
+	c := &common.Character{Name: "Sir Not-Appearing-in-this-Film", ActorID: 9999}
+	err := repo.Store(ctx, c)
+	assert.ErrorContains(err, "FOREIGN KEY constraint failed")
+
+	n, err := repo.Count(ctx, nil)
+	if assert.NoError(err) {
+		assert.Equal(81, n)
+	}
+
+	// Update
+	c, err = repo.Get(ctx, 1)
+	if !assert.NoError(err) || !assert.NotNil(c) {
+		return
+	}
+	c.ActorID = 9999
+	err = repo.Store(ctx, c)
+	assert.ErrorContains(err, "FOREIGN KEY constraint failed")
+
+	c, err = repo.Get(ctx, 1)
+	if assert.NoError(err) {
+		assert.Equal(int64(1), c.ActorID)
+	}
+}
+
+func testDeleteMissing(t *testing.T, repo common.CharacterRepository) {
+	err := repo.Delete(context.Background(), 9999)
+	assert.ErrorIs(t, err, common.ErrNotFound)
+}
+
+func testList(t *testing.T, repo common.CharacterRepository) {
+	cases := map[string]struct {
+		filters       *common.CharacterFilters
+		expected      int
+		expectedNames []string
+	}{
+		"Nil": {
+			filters:  nil,
+			expected: 81,
+		},
+		"All": {
+			filters:  &common.CharacterFilters{},
+			expected: 81,
+		},
+		"Eric Idle": {
+			filters: &common.CharacterFilters{
+				ActorID: 3,
+			},
+			expected: 8,
+			expectedNames: []string{
+				"Brother Maynard",
+				"Concorde",
+				"Dead Collector",
+				"First Swamp Castle Guard",
+				"Knight of Camelot",
+				"Peasant 1",
+				"Roger the Shrubber",
+				"Sir Robin the Not-Quite-So-Brave-as-Sir Launcelot",
+			},
+		},
+		"Sandy": {
+			filters: &common.CharacterFilters{
+				ActorName: "Sandy",
+			},
+			expected: 6,
+			expectedNames: []string{
+				"Girl in Castle Anthrax #3",
+				"Knight in Battle",
+				"Knight of Ni",
+				"Monk",
+				"Musician at Wedding",
+				"Villager at Witch Burning",
+			},
+		},
+		"Brother Maynard": {
+			filters: &common.CharacterFilters{
+				Name: "brother maynard",
+			},
+			expected: 2,
+			expectedNames: []string{
+				"Brother Maynard",
+				"Brother Maynard's Brother",
+			},
+		},
+		"The violence inherent in the system": {
+			filters: &common.CharacterFilters{
+				SceneNumber: 3,
+			},
+			expected: 4,
+			expectedNames: []string{
+				"Dennis",
+				"Dennis's Mother",
+				"King Arthur",
+				"Patsy",
+			},
+		},
+		"Actor name and scene": {
+			filters: &common.CharacterFilters{
+				ActorName:   "palin",
+				SceneNumber: 5,
+			},
+			expected: 2,
+			expectedNames: []string{
+				"Narrator",
+				"Peasant 2",
+			},
+		},
+		"Actor, name and scene": {
+			filters: &common.CharacterFilters{
+				ActorID:     6,
+				Name:        "KNIGHT",
+				SceneNumber: 6,
+			},
+			expected: 1,
+			expectedNames: []string{
+				"Knight of Camelot",
+			},
+		},
+		"Every filter": {
+			filters: &common.CharacterFilters{
+				ActorID:     6,
+				ActorName:   "palin",
+				Name:        "narrator",
+				SceneNumber: 5,
+			},
+			expected: 1,
+			expectedNames: []string{
+				"Narrator",
+			},
+		},
+		"No match": {
+			filters: &common.CharacterFilters{
+				Name: "Sir Not-Appearing-in-this-Film",
+			},
+			expected: 0,
+		},
+	}
+
+	for k, c := range cases {
+		t.Run(k, func(t *testing.T) {
+			assert := assert.New(t)
+
+			characters, err := repo.List(context.Background(), c.filters)
+			if !assert.NoError(err) {
+				return
+			}
+			assert.Len(characters, c.expected)
+
+			names := make([]string, 0, len(characters))
+			for _, ch := range characters {
+				if c.filters != nil && c.filters.ActorID != 0 {
+					assert.Equal(c.filters.ActorID, ch.ActorID)
+				}
+				names = append(names, ch.Name)
+			}
+			if c.expectedNames != nil {
+				assert.ElementsMatch(c.expectedNames, names)
+			}
+		})
+	}
+}
+
+func testListSets(t *testing.T, repo common.CharacterRepository) {
+	// More IDs than SQLite allows parameters in one query. None of them
+	// are real actors or scenes.
+	many := make([]int64, 40000)
+	for i := range many {
+		many[i] = int64(i) + 1000
+	}
+
+	cases := map[string]struct {
+		filters  *common.CharacterFilters
+		expected []int64
+	}{
+		"Actors": {
+			filters:  &common.CharacterFilters{ActorIDs: []int64{3, 4}},
+			expected: []int64{12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26},
+		},
+		"Exclude actors": {
+			filters:  &common.CharacterFilters{ActorIDs: []int64{3, 4}, ExcludeActorIDs: []int64{4}},
+			expected: []int64{12, 13, 14, 15, 16, 17, 18, 19},
+		},
+		"Any scene": {
+			filters:  &common.CharacterFilters{SceneNumbers: []int64{2, 3}},
+			expected: []int64{1, 12, 20, 27, 34, 51},
+		},
+		"All scenes": {
+			filters:  &common.CharacterFilters{SceneNumbers: []int64{21, 22, 23}, AllScenes: true},
+			expected: []int64{1, 28},
+		},
+		"All scenes with duplicates": {
+			filters:  &common.CharacterFilters{SceneNumbers: []int64{1, 3, 3, 1}, AllScenes: true},
+			expected: []int64{1, 20},
+		},
+		"Scene range": {
+			filters:  &common.CharacterFilters{SceneFrom: 11, SceneTo: 12},
+			expected: []int64{1, 23, 28, 42, 57},
+		},
+		"Scene range from": {
+			filters:  &common.CharacterFilters{SceneFrom: 21},
+			expected: []int64{1, 8, 10, 14, 19, 23, 25, 28, 37, 39, 53, 61},
+		},
+		"Scene range to": {
+			filters:  &common.CharacterFilters{SceneTo: 2},
+			expected: []int64{1, 5, 12, 20, 32, 51},
+		},
+		"Scenes and range": {
+			filters:  &common.CharacterFilters{SceneNumbers: []int64{1, 3}, AllScenes: true, SceneFrom: 11, SceneTo: 12},
+			expected: []int64{1},
+		},
+		"Many actors": {
+			filters:  &common.CharacterFilters{ActorIDs: append([]int64{3}, many...)},
+			expected: []int64{12, 13, 14, 15, 16, 17, 18, 19},
+		},
+		"Many excluded actors": {
+			filters:  &common.CharacterFilters{ActorID: 3, ExcludeActorIDs: many},
+			expected: []int64{12, 13, 14, 15, 16, 17, 18, 19},
+		},
+		"Many scenes": {
+			filters:  &common.CharacterFilters{SceneNumbers: append(many, 2)},
+			expected: []int64{12, 51},
+		},
+		"Many scenes, all": {
+			filters:  &common.CharacterFilters{SceneNumbers: append(many, 2), AllScenes: true},
+			expected: nil,
+		},
+	}
+
+	for k, c := range cases {
+		t.Run(k, func(t *testing.T) {
+			assert := assert.New(t)
+			ctx := context.Background()
+
+			characters, err := repo.List(ctx, c.filters)
+			if !assert.NoError(err) {
+				return
+			}
+
+			var ids []int64
+			for _, ch := range characters {
+				ids = append(ids, ch.ID)
+			}
+			assert.Equal(c.expected, ids)
+
+			n, err := repo.Count(ctx, c.filters)
+			if assert.NoError(err) {
+				assert.Equal(len(c.expected), n)
+			}
+		})
+	}
+}
+
+func testListOrder(t *testing.T, repo common.CharacterRepository) {
+	cases := map[string]struct {
+		filters  *common.CharacterFilters
+		expected []int64
+	}{
+		"Default": {
+			filters:  nil,
+			expected: []int64{1, 2, 3},
+		},
+		"Name": {
+			filters:  &common.CharacterFilters{OrderBy: common.OrderByName},
+			expected: []int64{25, 19, 41, 17},
+		},
+		"Name descending": {
+			filters:  &common.CharacterFilters{OrderBy: common.OrderByName, Descending: true},
+			expected: []int64{44, 2, 32},
+		},
+		"ID descending": {
+			filters:  &common.CharacterFilters{OrderBy: common.OrderByID, Descending: true},
+			expected: []int64{81, 80, 79},
+		},
+		"Actor name": {
+			filters:  &common.CharacterFilters{OrderBy: common.OrderByActorName},
+			expected: []int64{68, 70, 54},
+		},
+		// Sir Bedevere and Sir Lancelot are both in 10 scenes, so the tie
+		// is broken by ID, also descending.
+		"Scene count": {
+			filters:  &common.CharacterFilters{OrderBy: common.OrderBySceneCount, Descending: true},
+			expected: []int64{1, 28, 8, 37},
+		},
+		"Quote count": {
+			filters:  &common.CharacterFilters{OrderBy: common.OrderByQuoteCount, Descending: true},
+			expected: []int64{1, 42, 34, 28, 20},
+		},
+		"Quote count ascending": {
+			filters:  &common.CharacterFilters{OrderBy: common.OrderByQuoteCount},
+			expected: []int64{2, 3, 4},
+		},
+		"Filtered": {
+			filters:  &common.CharacterFilters{ActorID: 3, OrderBy: common.OrderByName},
+			expected: []int64{19, 17, 12, 16, 15, 13, 18, 14},
+		},
+	}
+
+	for k, c := range cases {
+		t.Run(k, func(t *testing.T) {
+			characters, err := repo.List(context.Background(), c.filters)
+			if !assert.NoError(t, err) || !assert.True(t, len(characters) >= len(c.expected)) {
+				return
+			}
+
+			ids := make([]int64, len(c.expected))
+			for i := range ids {
+				ids[i] = characters[i].ID
+			}
+			assert.Equal(t, c.expected, ids)
+		})
+	}
+
+	t.Run("Pages", func(t *testing.T) {
+		assert := assert.New(t)
+		ctx := context.Background()
+		filters := &common.CharacterFilters{OrderBy: common.OrderBySceneCount, Descending: true}
+
+		all, err := repo.List(ctx, filters)
+		if !assert.NoError(err) {
+			return
+		}
+
+		var paged []*common.Character
+		for _, p := range listPages(t, repo, filters, 10, false) {
+			paged = append(paged, p.Characters...)
+		}
+		assert.Equal(all, paged)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		assert := assert.New(t)
+		ctx := context.Background()
+
+		filters := &common.CharacterFilters{OrderBy: "name; DROP TABLE characters"}
+		_, err := repo.List(ctx, filters)
+		assert.True(errors.Is(err, common.ErrInvalidOrder), "%v", err)
+
+		_, err = repo.ListPage(ctx, filters, common.Page{Limit: 10})
+		assert.True(errors.Is(err, common.ErrInvalidOrder), "%v", err)
+
+		// Cursors only hold a name and ID.
+		cursor := common.EncodeCursor(common.Cursor{Name: "Patsy", ID: 20})
+		_, err = repo.ListPage(ctx, &common.CharacterFilters{OrderBy: common.OrderByID}, common.Page{Limit: 10, Cursor: cursor})
+		assert.True(errors.Is(err, common.ErrInvalidPage), "%v", err)
+	})
+}
+
+func testListPage(t *testing.T, repo common.CharacterRepository) {
+	// 81 characters is a multiple of 9 but not of 10, so the last page is
+	// full in one case and short in the other.
+	for _, limit := range []int{9, 10} {
+		for _, cursor := range []bool{true, false} {
+			name := fmt.Sprintf("Offset/%d", limit)
+			if cursor {
+				name = fmt.Sprintf("Cursor/%d", limit)
+			}
+
+			t.Run(name, func(t *testing.T) {
+				pages := listPages(t, repo, nil, limit, cursor)
+				checkPages(t, pages, limit, 81)
+			})
+		}
+	}
+
+	t.Run("Filtered", func(t *testing.T) {
+		// There are five characters named "Knight of Camelot", so the cursor
+		// has to break ties on ID.
+		filters := &common.CharacterFilters{Name: "knight of camelot"}
+		pages := listPages(t, repo, filters, 2, true)
+		checkPages(t, pages, 2, 5)
+	})
+
+	t.Run("PastEnd", func(t *testing.T) {
+		p, err := repo.ListPage(context.Background(), nil, common.Page{Limit: 10, Offset: 100})
+		if assert.NoError(t, err) {
+			assert.Empty(t, p.Characters)
+			assert.False(t, p.HasMore)
+		}
+	})
+}
+
+// listPages calls ListPage until there are no more pages, moving to the next
+// page with the cursor or the offset.
+func listPages(t *testing.T, repo common.CharacterRepository, filters *common.CharacterFilters, limit int, cursor bool) []*common.CharacterPage {
+	t.Helper()
+
+	var pages []*common.CharacterPage
+	page := common.Page{Limit: limit}
+
+	// The limit on the number of pages stops a broken HasMore from looping
+	// forever.
+	for len(pages) < 100 {
+		p, err := repo.ListPage(context.Background(), filters, page)
+		if err != nil {
+			t.Fatalf("page %d: %s", len(pages), err)
+		}
+
+		pages = append(pages, p)
+		if !p.HasMore {
+			break
+		}
+
+		if cursor {
+			page.Cursor = p.NextCursor
+		} else {
+			page.Offset += limit
+		}
+	}
+
+	return pages
+}
+
+// checkPages checks that pages have every character exactly once, in order of
+// name and ID.
+func checkPages(t *testing.T, pages []*common.CharacterPage, limit, total int) {
+	t.Helper()
+	assert := assert.New(t)
+
+	var list []*common.Character
+	for i, p := range pages {
+		last := i == len(pages)-1
+		assert.Equal(!last, p.HasMore, "page %d", i)
+		assert.Equal(!last, p.NextCursor != "", "page %d", i)
+		if !last {
+			assert.Len(p.Characters, limit, "page %d", i)
+		}
+		list = append(list, p.Characters...)
+	}
+
+	assert.Len(list, total)
+
+	seen := map[int64]bool{}
+	for i, c := range list {
+		assert.False(seen[c.ID], "character %d is on more than one page", c.ID)
+		seen[c.ID] = true
+
+		if i > 0 {
+			prev := list[i-1]
+			assert.True(prev.Name < c.Name || (prev.Name == c.Name && prev.ID < c.ID),
+				"%q (%d) is before %q (%d)", prev.Name, prev.ID, c.Name, c.ID)
+		}
+	}
+}
+
+func testListPageInvalid(t *testing.T, repo common.CharacterRepository) {
+	ctx := context.Background()
+
+	_, err := repo.ListPage(ctx, nil, common.Page{})
+	assert.True(t, errors.Is(err, common.ErrInvalidPage), "%v", err)
+
+	_, err = repo.ListPage(ctx, nil, common.Page{Limit: 10, Cursor: "Patsy"})
+	assert.True(t, errors.Is(err, common.ErrInvalidCursor), "%v", err)
+}
+
+func testCount(t *testing.T, repo common.CharacterRepository) {
+	cases := map[string]struct {
+		filters  *common.CharacterFilters
+		expected int
+	}{
+		"All": {
+			filters:  nil,
+			expected: 81,
+		},
+		"Actor": {
+			filters:  &common.CharacterFilters{ActorID: 3},
+			expected: 8,
+		},
+		"Actor name": {
+			filters:  &common.CharacterFilters{ActorName: "sandy"},
+			expected: 6,
+		},
+		"Name": {
+			filters:  &common.CharacterFilters{Name: "knight", OrderBy: common.OrderByQuoteCount},
+			expected: 10,
+		},
+		"Scene": {
+			filters:  &common.CharacterFilters{SceneNumber: 3},
+			expected: 4,
+		},
+		"None": {
+			filters:  &common.CharacterFilters{Name: "nobody"},
+			expected: 0,
+		},
+	}
+
+	for k, c := range cases {
+		t.Run(k, func(t *testing.T) {
+			assert := assert.New(t)
+			ctx := context.Background()
+
+			n, err := repo.Count(ctx, c.filters)
+			if !assert.NoError(err) {
+				return
+			}
+			assert.Equal(c.expected, n)
+
+			characters, err := repo.List(ctx, c.filters)
+			if !assert.NoError(err) {
+				return
+			}
+			assert.Len(characters, n)
+		})
+	}
+}
+
+func testListWithFacets(t *testing.T, repo common.CharacterRepository) {
+	t.Run("All", func(t *testing.T) {
+		assert := assert.New(t)
+
+		list, err := repo.ListWithFacets(context.Background(), nil)
+		if !assert.NoError(err) {
+			return
+		}
+
+		assert.Len(list.Characters, 81)
+		assert.Len(list.Facets.Actors, 35)
+		assert.Len(list.Facets.Scenes, 23)
+
+		total := 0
+		for _, f := range list.Facets.Actors {
+			total += f.Count
+		}
+		assert.Equal(81, total)
+	})
+
+	t.Run("Name", func(t *testing.T) {
+		assert := assert.New(t)
+		ctx := context.Background()
+		filters := &common.CharacterFilters{Name: "knight", OrderBy: common.OrderByName}
+
+		list, err := repo.ListWithFacets(ctx, filters)
+		if !assert.NoError(err) {
+			return
+		}
+
+		characters, err := repo.List(ctx, filters)
+		if !assert.NoError(err) {
+			return
+		}
+		assert.Equal(characters, list.Characters)
+
+		assert.Equal([]common.FacetCount{
+			{ID: 2, Count: 2},
+			{ID: 3, Count: 1},
+			{ID: 4, Count: 2},
+			{ID: 5, Count: 1},
+			{ID: 6, Count: 2},
+			{ID: 18, Count: 2},
+		}, list.Facets.Actors)
+		assert.Equal([]common.FacetCount{
+			{ID: 4, Count: 2},
+			{ID: 6, Count: 5},
+			{ID: 12, Count: 2},
+			{ID: 18, Count: 2},
+			{ID: 23, Count: 1},
+		}, list.Facets.Scenes)
+	})
+
+	t.Run("Actor name", func(t *testing.T) {
+		assert := assert.New(t)
+
+		list, err := repo.ListWithFacets(context.Background(), &common.CharacterFilters{ActorName: "sandy"})
+		if !assert.NoError(err) {
+			return
+		}
+
+		assert.Len(list.Characters, 6)
+		assert.Equal([]common.FacetCount{
+			{ID: 18, Count: 5},
+			{ID: 19, Count: 1},
+		}, list.Facets.Actors)
+	})
+
+	// The characters in scene 3 are in other scenes too, and those are
+	// counted.
+	t.Run("Scene", func(t *testing.T) {
+		assert := assert.New(t)
+
+		list, err := repo.ListWithFacets(context.Background(), &common.CharacterFilters{SceneNumber: 3})
+		if !assert.NoError(err) {
+			return
+		}
+
+		assert.Len(list.Characters, 4)
+		assert.Equal([]common.FacetCount{
+			{ID: 1, Count: 1},
+			{ID: 4, Count: 1},
+			{ID: 5, Count: 1},
+			{ID: 6, Count: 1},
+		}, list.Facets.Actors)
+		assert.Contains(list.Facets.Scenes, common.FacetCount{ID: 3, Count: 4})
+		assert.Greater(len(list.Facets.Scenes), 1)
+	})
+
+	t.Run("None", func(t *testing.T) {
+		assert := assert.New(t)
+
+		list, err := repo.ListWithFacets(context.Background(), &common.CharacterFilters{Name: "nobody"})
+		if !assert.NoError(err) {
+			return
+		}
+
+		assert.Empty(list.Characters)
+		assert.Empty(list.Facets.Actors)
+		assert.Empty(list.Facets.Scenes)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := repo.ListWithFacets(context.Background(), &common.CharacterFilters{OrderBy: "random()"})
+		assert.True(t, errors.Is(err, common.ErrInvalidOrder), "%v", err)
+	})
+}
@@ -0,0 +1,186 @@
+// Package sqltest records the SQL that a store runs, so tests can check the
+// queries for dialects that there's no database for.
+package sqltest
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files")
+
+// driverName is the name the recording driver is registered under.
+const driverName = "sqltest"
+
+var (
+	recordersMu sync.Mutex
+	recorders   = map[string]*Recorder{}
+)
+
+func init() {
+	sql.Register(driverName, recordingDriver{})
+}
+
+// Recorder keeps every statement sent to a database opened with Open.
+//
+// Queries return no rows, and statements report one affected row with an
+// insert ID of 1.
+type Recorder struct {
+	mu         sync.Mutex
+	statements []string
+}
+
+// Open returns a database that records every statement instead of running
+// it. The database is closed when the test finishes.
+func Open(t testing.TB) (*sql.DB, *Recorder) {
+	t.Helper()
+
+	rec := &Recorder{}
+	dsn := t.Name()
+
+	recordersMu.Lock()
+	recorders[dsn] = rec
+	recordersMu.Unlock()
+
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		t.Fatalf("Error opening recorder: %s", err)
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+
+		recordersMu.Lock()
+		delete(recorders, dsn)
+		recordersMu.Unlock()
+	})
+
+	return db, rec
+}
+
+// Section adds a heading to the output, so the golden file shows which
+// statements came from which call.
+func (r *Recorder) Section(name string) {
+	r.add("-- " + name)
+}
+
+// String returns the recorded statements, one per line, with their
+// arguments.
+func (r *Recorder) String() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return strings.Join(r.statements, "\n") + "\n"
+}
+
+func (r *Recorder) add(s string) {
+	r.mu.Lock()
+	r.statements = append(r.statements, s)
+	r.mu.Unlock()
+}
+
+func (r *Recorder) record(query string, args []driver.NamedValue) {
+	values := make([]string, len(args))
+	for i, a := range args {
+		values[i] = fmt.Sprintf("%#v", a.Value)
+	}
+	r.add(query + "\n   args: [" + strings.Join(values, ", ") + "]")
+}
+
+// Golden compares got with the contents of testdata/name.golden. With the
+// -update flag, the file is rewritten instead.
+func Golden(t testing.TB, name, got string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		err := os.MkdirAll(filepath.Dir(path), 0o755)
+		if err == nil {
+			err = os.WriteFile(path, []byte(got), 0o644)
+		}
+		if err != nil {
+			t.Fatalf("Error updating golden file: %s", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Error reading golden file (run with -update to create it): %s", err)
+	}
+
+	if got != string(want) {
+		t.Errorf("%s does not match (run with -update to rewrite it)\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+type recordingDriver struct{}
+
+func (recordingDriver) Open(name string) (driver.Conn, error) {
+	recordersMu.Lock()
+	rec, ok := recorders[name]
+	recordersMu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("sqltest: no recorder for %q", name)
+	}
+
+	return &conn{rec: rec}, nil
+}
+
+type conn struct {
+	rec *Recorder
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("sqltest: prepared statements are not supported")
+}
+
+func (c *conn) Close() error {
+	return nil
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	c.rec.add("BEGIN")
+	return c, nil
+}
+
+func (c *conn) Commit() error {
+	c.rec.add("COMMIT")
+	return nil
+}
+
+func (c *conn) Rollback() error {
+	c.rec.add("ROLLBACK")
+	return nil
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.rec.record(query, args)
+	return result{}, nil
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.rec.record(query, args)
+	return rows{}, nil
+}
+
+type result struct{}
+
+func (result) LastInsertId() (int64, error) { return 1, nil }
+func (result) RowsAffected() (int64, error) { return 1, nil }
+
+type rows struct{}
+
+func (rows) Columns() []string              { return nil }
+func (rows) Close() error                   { return nil }
+func (rows) Next(dest []driver.Value) error { return io.EOF }
@@ -0,0 +1,93 @@
+package common
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var (
+	sharedTestDBOnce sync.Once
+	sharedTestDB     *sql.DB
+	sharedTestDBErr  error
+
+	savepointID atomic.Int64
+)
+
+// testTxWait is how long TestTx waits for another test to release the shared
+// database before giving up.
+const testTxWait = time.Minute
+
+// TestTx returns a transaction on an in-memory database that is shared by
+// every test in the package. The schema and test data are loaded the first
+// time TestTx is called, and the transaction is rolled back when the test
+// finishes, so the next test starts from the same data.
+//
+// SQLite only allows one writer at a time, so tests that use TestTx take
+// turns, even when they are parallel. Parallel tests must call t.Parallel
+// before TestTx, otherwise they will hold the database while paused.
+func TestTx(t testing.TB) *sql.Tx {
+	t.Helper()
+
+	sharedTestDBOnce.Do(func() {
+		sharedTestDB, sharedTestDBErr = openTestDB(true)
+	})
+	if sharedTestDBErr != nil {
+		t.Fatalf("Error opening database: %s", sharedTestDBErr)
+	}
+
+	// The context passed to BeginTx applies to the whole transaction, so the
+	// connection is acquired separately with a time limit.
+	ctx, cancel := context.WithTimeout(context.Background(), testTxWait)
+	defer cancel()
+
+	conn, err := sharedTestDB.Conn(ctx)
+	if err != nil {
+		t.Fatalf("Error waiting for the test database (is another test holding it?): %s", err)
+	}
+
+	tx, err := conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		conn.Close()
+		t.Fatalf("Error starting transaction: %s", err)
+	}
+
+	t.Cleanup(func() {
+		tx.Rollback()
+		conn.Close()
+	})
+
+	return tx
+}
+
+// TestSavepoint creates a savepoint in tx that is rolled back when the test
+// finishes. It's intended for subtests that share their parent's
+// transaction from TestTx, so each subtest starts with the parent's data.
+// Subtests that share a transaction must not be parallel.
+//
+// TestSavepoint returns tx, for convenience.
+func TestSavepoint(t testing.TB, tx *sql.Tx) *sql.Tx {
+	t.Helper()
+
+	name := fmt.Sprintf("test_%d", savepointID.Add(1))
+	_, err := tx.Exec("SAVEPOINT " + name)
+	if err != nil {
+		t.Fatalf("Error creating savepoint: %s", err)
+	}
+
+	t.Cleanup(func() {
+		_, err := tx.Exec("ROLLBACK TO " + name)
+		if err == nil {
+			_, err = tx.Exec("RELEASE " + name)
+		}
+		if err != nil {
+			t.Errorf("Error rolling back savepoint: %s", err)
+		}
+	})
+
+	return tx
+}
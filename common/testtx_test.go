@@ -0,0 +1,71 @@
+package common
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTestTx(t *testing.T) {
+	// Each of these adds a character. None of them should see the others.
+	for i := 0; i < 4; i++ {
+		i := i
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			t.Parallel()
+
+			tx := TestTx(t)
+			assertCharacterCount(t, tx, 81)
+
+			_, err := tx.Exec(`INSERT INTO characters (actor_id, name) VALUES (1, ?)`, fmt.Sprintf("Sir Not-Appearing-in-this-Film %d", i))
+			if assert.NoError(t, err) {
+				assertCharacterCount(t, tx, 82)
+			}
+		})
+	}
+}
+
+func TestTestSavepoint(t *testing.T) {
+	tx := TestTx(t)
+
+	_, err := tx.Exec(`DELETE FROM quotes`)
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = tx.Exec(`DELETE FROM scene_characters`)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for i := 0; i < 2; i++ {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			tx := TestSavepoint(t, tx)
+			assertCharacterCount(t, tx, 81)
+
+			_, err := tx.Exec(`DELETE FROM characters WHERE actor_id = 3`)
+			if assert.NoError(t, err) {
+				assertCharacterCount(t, tx, 73)
+			}
+
+			// The parent's changes are visible.
+			var quotes int
+			err = tx.QueryRow(`SELECT COUNT(*) FROM quotes`).Scan(&quotes)
+			if assert.NoError(t, err) {
+				assert.Zero(t, quotes)
+			}
+		})
+	}
+
+	assertCharacterCount(t, tx, 81)
+}
+
+func assertCharacterCount(t *testing.T, tx *sql.Tx, expected int) {
+	t.Helper()
+
+	var count int
+	err := tx.QueryRow(`SELECT COUNT(*) FROM characters`).Scan(&count)
+	if assert.NoError(t, err) {
+		assert.Equal(t, expected, count)
+	}
+}
@@ -8,10 +8,11 @@ import (
 	"strings"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/pboyd/godbmodels/common"
 )
 
-// ErrNotFound is returned when updating or deleting a character that does not
-// exist in the database.
+// ErrNotFound is returned when updating or deleting a character or quote that
+// does not exist in the database.
 var ErrNotFound = errors.New("not found")
 
 // Character is one character from the database.
@@ -23,12 +24,15 @@ type Character struct {
 
 // CharacterStore loads and updates characters in the database.
 type CharacterStore struct {
-	dbx *sqlx.DB
+	dbx     ext
+	dialect *common.Dialect
 }
 
-// NewCharacterStore creates a new CharacterStore.
-func NewCharacterStore(db *sql.DB) *CharacterStore {
-	return &CharacterStore{dbx: sqlx.NewDb(db, "sqlite3")}
+// NewCharacterStore creates a new CharacterStore. db can be a *sql.DB or a
+// *sql.Tx.
+func NewCharacterStore[T DB](db T, opts ...Option) *CharacterStore {
+	o := newOptions(opts)
+	return &CharacterStore{dbx: newExt(db, o.dialect), dialect: o.dialect}
 }
 
 // Get loads a character from the database by ID.
@@ -36,7 +40,7 @@ func NewCharacterStore(db *sql.DB) *CharacterStore {
 // If no character is found, Get returns a nil Character and no error.
 func (cs *CharacterStore) Get(ctx context.Context, id int64) (*Character, error) {
 	var c Character
-	err := cs.dbx.GetContext(ctx, &c, `SELECT id, actor_id, name FROM characters WHERE id = $1`, id)
+	err := cs.dbx.GetContext(ctx, &c, cs.dialect.Rebind(`SELECT id, actor_id, name FROM characters WHERE id = ?`), id)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, nil
 	}
@@ -58,24 +62,27 @@ func (cs *CharacterStore) Store(ctx context.Context, c *Character) error {
 }
 
 func (cs *CharacterStore) insert(ctx context.Context, c *Character) error {
-	rows, err := cs.dbx.NamedQueryContext(ctx, `INSERT INTO characters (actor_id, name) VALUES (:actor_id, :name) RETURNING id`, c)
+	query, args, err := sqlx.Named(`INSERT INTO characters (actor_id, name) VALUES (:actor_id, :name)`, c)
 	if err != nil {
 		return fmt.Errorf("insert character: %w", err)
 	}
-	defer rows.Close()
 
-	for rows.Next() {
-		err := rows.Scan(&c.ID)
-		if err != nil {
-			return fmt.Errorf("insert character: %w", err)
-		}
+	id, err := cs.dialect.Insert(ctx, cs.dbx, query, args...)
+	if err != nil {
+		return fmt.Errorf("insert character: %w", err)
 	}
 
+	c.ID = id
 	return nil
 }
 
 func (cs *CharacterStore) update(ctx context.Context, c *Character) error {
-	res, err := cs.dbx.NamedExecContext(ctx, `UPDATE characters SET actor_id = :actor_id, name = :name WHERE id = :id`, c)
+	query, args, err := sqlx.Named(`UPDATE characters SET actor_id = :actor_id, name = :name WHERE id = :id`, c)
+	if err != nil {
+		return fmt.Errorf("update character: %w", err)
+	}
+
+	res, err := cs.dbx.ExecContext(ctx, cs.dialect.Rebind(query), args...)
 	if err != nil {
 		return fmt.Errorf("update character: %w", err)
 	}
@@ -92,7 +99,7 @@ func (cs *CharacterStore) update(ctx context.Context, c *Character) error {
 //
 // If the character does not exist in the database, Delete returns ErrNotFound.
 func (cs *CharacterStore) Delete(ctx context.Context, id int64) error {
-	res, err := cs.dbx.ExecContext(ctx, `DELETE FROM characters WHERE id = $1`, id)
+	res, err := cs.dbx.ExecContext(ctx, cs.dialect.Rebind(`DELETE FROM characters WHERE id = ?`), id)
 	if err != nil {
 		return fmt.Errorf("delete character: %w", err)
 	}
@@ -118,40 +125,182 @@ type CharacterFilters struct {
 
 	// SceneNumber filters by the scene that the character appears in.
 	SceneNumber int64
+
+	// ActorIDs matches characters played by any of these actors.
+	ActorIDs []int64
+
+	// ExcludeActorIDs leaves out characters played by any of these actors.
+	ExcludeActorIDs []int64
+
+	// SceneNumbers matches characters that appear in any of these scenes,
+	// or in every one of them if AllScenes is set.
+	SceneNumbers []int64
+
+	// AllScenes makes SceneNumbers match characters that appear in every
+	// listed scene.
+	AllScenes bool
+
+	// SceneFrom and SceneTo match characters that appear in at least one
+	// scene in the range, inclusive. Either can be zero to leave that end
+	// of the range open.
+	SceneFrom int64
+	SceneTo   int64
+
+	// OrderBy sorts the results. List defaults to common.OrderByID and
+	// ListPage defaults to common.OrderByName.
+	OrderBy common.OrderBy
+
+	// Descending reverses the order.
+	Descending bool
 }
 
 // List searches for characters in the database.
 //
-// If filters is nil, all characters are returned. Otherwise, the results are
-// filtered by the criteria in filters.
+// If filters is nil, all characters are returned, in order of ID. Otherwise,
+// the results are filtered and sorted by the criteria in filters. If
+// filters.OrderBy is invalid, the error wraps common.ErrInvalidOrder.
 func (cs *CharacterStore) List(ctx context.Context, filters *CharacterFilters) ([]*Character, error) {
-	var args []interface{}
-	query := "SELECT c.id, c.actor_id, c.name FROM characters c"
-	joins := []string{}
-	where := []string{}
-
-	if filters != nil {
-		if filters.ActorID != 0 {
-			where = append(where, "c.actor_id = ?")
-			args = append(args, filters.ActorID)
-		} else if filters.ActorName != "" {
-			joins = append(joins, "JOIN actors a ON a.id = c.actor_id")
-			where = append(where, "LOWER(a.name) LIKE ?")
-			args = append(args, "%"+strings.ToLower(filters.ActorName)+"%")
-		}
-
-		if filters.Name != "" {
-			where = append(where, "LOWER(c.name) LIKE ?")
-			args = append(args, "%"+strings.ToLower(filters.Name)+"%")
-		}
-
-		if filters.SceneNumber != 0 {
-			joins = append(joins, "JOIN scene_characters sc ON sc.character_id = c.id")
-			where = append(where, "sc.scene_id = ?")
-			args = append(args, filters.SceneNumber)
-		}
+	orderBy, err := filters.orderClause(common.OrderByID)
+	if err != nil {
+		return nil, err
+	}
+
+	joins, where, args := cs.filterClauses(filters)
+	return cs.list(ctx, listQuery(joins, where)+orderBy, args)
+}
+
+// CharacterPage is one page of characters from ListPage.
+type CharacterPage struct {
+	Characters []*Character
+
+	// HasMore is true if there are characters after this page.
+	HasMore bool
+
+	// NextCursor continues from the last character on this page. It's empty
+	// if HasMore is false.
+	NextCursor string
+}
+
+// ListPage is like List, but returns one page of the results. The default
+// order is by name.
+//
+// If page is invalid, the error wraps common.ErrInvalidPage or
+// common.ErrInvalidCursor.
+func (cs *CharacterStore) ListPage(ctx context.Context, filters *CharacterFilters, page common.Page) (*CharacterPage, error) {
+	orderBy, err := filters.orderClause(common.OrderByName)
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := page.After(filters.order(common.OrderByName))
+	if err != nil {
+		return nil, err
+	}
+
+	joins, where, args := cs.filterClauses(filters)
+	if after != nil {
+		where = append(where, "(c.name > ? OR (c.name = ? AND c.id > ?))")
+		args = append(args, after.Name, after.Name, after.ID)
+	}
+
+	// One extra row is fetched to find out if there's another page.
+	query := listQuery(joins, where) + orderBy + " LIMIT ? OFFSET ?"
+	args = append(args, page.Limit+1, page.Offset)
+
+	characters, err := cs.list(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &CharacterPage{}
+	p.Characters, p.HasMore, p.NextCursor = common.TrimPage(characters, page.Limit, func(c *Character) common.Cursor {
+		return common.Cursor{Name: c.Name, ID: c.ID}
+	})
+	return p, nil
+}
+
+// Count returns the number of characters that List would return for filters.
+// filters.OrderBy and Descending are ignored.
+func (cs *CharacterStore) Count(ctx context.Context, filters *CharacterFilters) (int, error) {
+	joins, where, args := cs.filterClauses(filters)
+	query := "SELECT COUNT(*) FROM (" + listQuery(joins, where) + ") l"
+
+	var n int
+	err := cs.dbx.GetContext(ctx, &n, cs.dialect.Rebind(query), args...)
+	if err != nil {
+		return 0, fmt.Errorf("count characters: %w", err)
+	}
+
+	return n, nil
+}
+
+// CharacterList is a list of characters from ListWithFacets.
+type CharacterList struct {
+	Characters []*Character
+	Facets     common.Facets
+}
+
+// ListWithFacets is like List, but also counts the matching characters by
+// actor and by scene. The counts are grouped by the database.
+func (cs *CharacterStore) ListWithFacets(ctx context.Context, filters *CharacterFilters) (*CharacterList, error) {
+	characters, err := cs.List(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	joins, where, args := cs.filterClauses(filters)
+	base := listQuery(joins, where)
+
+	// The columns are named to match common.FacetCount's fields, which the
+	// mapper lowercases.
+	l := &CharacterList{Characters: characters}
+	err = cs.dbx.SelectContext(ctx, &l.Facets.Actors, cs.dialect.Rebind("SELECT l.actor_id AS id, COUNT(*) AS count FROM ("+base+") l GROUP BY l.actor_id ORDER BY l.actor_id"), args...)
+	if err != nil {
+		return nil, fmt.Errorf("count characters by actor: %w", err)
+	}
+
+	err = cs.dbx.SelectContext(ctx, &l.Facets.Scenes, cs.dialect.Rebind("SELECT fsc.scene_id AS id, COUNT(*) AS count FROM ("+base+") l JOIN scene_characters fsc ON fsc.character_id = l.id GROUP BY fsc.scene_id ORDER BY fsc.scene_id"), args...)
+	if err != nil {
+		return nil, fmt.Errorf("count characters by scene: %w", err)
 	}
 
+	return l, nil
+}
+
+// order returns the order the results are sorted in, which is def unless
+// filters says otherwise.
+func (filters *CharacterFilters) order(def common.OrderBy) (common.OrderBy, bool) {
+	if filters == nil {
+		return def, false
+	}
+	return filters.OrderBy.Or(def), filters.Descending
+}
+
+// orderClause returns the ORDER BY clause for filters.
+func (filters *CharacterFilters) orderClause(def common.OrderBy) (string, error) {
+	order, desc := filters.order(def)
+	clause, err := common.OrderClause("c", order, desc)
+	if err != nil {
+		return "", err
+	}
+
+	return " ORDER BY " + strings.Join(clause, ", "), nil
+}
+
+// filterClauses returns the joins, WHERE conditions and arguments that apply
+// filters to a query on characters c.
+func (cs *CharacterStore) filterClauses(filters *CharacterFilters) (joins, where []string, args []interface{}) {
+	if filters == nil {
+		return
+	}
+
+	f := common.CharacterFilters(*filters)
+	return cs.dialect.CharacterFilterClauses(&f)
+}
+
+func listQuery(joins, where []string) string {
+	query := "SELECT c.id, c.actor_id, c.name FROM characters c"
+
 	if len(joins) > 0 {
 		query += " " + strings.Join(joins, " ")
 	}
@@ -160,8 +309,12 @@ func (cs *CharacterStore) List(ctx context.Context, filters *CharacterFilters) (
 		query += " WHERE " + strings.Join(where, " AND ")
 	}
 
+	return query
+}
+
+func (cs *CharacterStore) list(ctx context.Context, query string, args []interface{}) ([]*Character, error) {
 	var characters []*Character
-	err := cs.dbx.SelectContext(ctx, &characters, query, args...)
+	err := cs.dbx.SelectContext(ctx, &characters, cs.dialect.Rebind(query), args...)
 	if err != nil {
 		return nil, fmt.Errorf("list characters: %w", err)
 	}
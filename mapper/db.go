@@ -0,0 +1,122 @@
+package mapper
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/reflectx"
+	"github.com/pboyd/godbmodels/common"
+)
+
+// DB is satisfied by *sql.DB and *sql.Tx, so the stores can be used inside a
+// transaction.
+//
+// sqlx needs to know which of the two it's wrapping, so unlike the other
+// packages this is a type constraint rather than an interface.
+type DB interface {
+	*sql.DB | *sql.Tx
+}
+
+// ext is implemented by both *sqlx.DB and *sqlx.Tx.
+type ext interface {
+	sqlx.ExtContext
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+func newExt[T DB](db T, d *common.Dialect) ext {
+	switch db := interface{}(db).(type) {
+	case *sql.Tx:
+		// sqlx can't wrap an existing transaction, but this is the same
+		// thing sqlx.DB.Beginx would return, except that it doesn't know
+		// the bind type. Named parameters are bound by the store's dialect,
+		// so sqlx doesn't need it.
+		return &sqlx.Tx{Tx: db, Mapper: reflectx.NewMapperFunc("db", strings.ToLower)}
+	default:
+		return sqlx.NewDb(db.(*sql.DB), sqlxDriverName(d))
+	}
+}
+
+// sqlxDriverName registers the dialect's bind type with sqlx and returns the
+// name it's registered under. sqlx only knows the bind types of the drivers
+// it was built with, which doesn't include modernc.org/sqlite, and the driver
+// name alone says nothing about the dialect the store was given.
+func sqlxDriverName(d *common.Dialect) string {
+	bindType := sqlx.QUESTION
+	if d.Placeholders == common.DollarPlaceholders {
+		bindType = sqlx.DOLLAR
+	}
+
+	name := "godbmodels-" + d.Name
+	sqlx.BindDriver(name, bindType)
+	return name
+}
+
+// inTx calls fn with a transaction, which is committed if fn returns nil and
+// rolled back otherwise.
+//
+// If db is a *sqlx.DB, a transaction is started. Otherwise db is already a
+// transaction, and fn runs inside a savepoint so its changes are undone on
+// error without ending the caller's transaction.
+func inTx(ctx context.Context, db ext, fn func(tx ext) error) error {
+	if d, ok := db.(*sqlx.DB); ok {
+		tx, err := d.BeginTxx(ctx, nil)
+		if err != nil {
+			return err
+		}
+
+		err = fn(tx)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		return tx.Commit()
+	}
+
+	savepoint := fmt.Sprintf("mapper_%d", savepointID.Add(1))
+	_, err := db.ExecContext(ctx, "SAVEPOINT "+savepoint)
+	if err != nil {
+		return err
+	}
+
+	err = fn(db)
+	if err != nil {
+		db.ExecContext(ctx, "ROLLBACK TO "+savepoint)
+		db.ExecContext(ctx, "RELEASE "+savepoint)
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, "RELEASE "+savepoint)
+	return err
+}
+
+var savepointID atomic.Int64
+
+// Option configures a store.
+type Option func(*options)
+
+type options struct {
+	dialect *common.Dialect
+}
+
+// WithDialect sets the SQL dialect the store writes queries in. The default
+// is common.SQLite.
+func WithDialect(d *common.Dialect) Option {
+	return func(o *options) {
+		o.dialect = d
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{dialect: common.SQLite}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
@@ -0,0 +1,89 @@
+package mapper
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// Include selects the relations GetDetail loads.
+type Include uint
+
+const (
+	// IncludeActor loads CharacterDetail.Actor.
+	IncludeActor Include = 1 << iota
+
+	// IncludeScenes loads CharacterDetail.Scenes, in scene order.
+	IncludeScenes
+
+	// IncludeQuotes loads CharacterDetail.Quotes, in order of ID.
+	IncludeQuotes
+
+	// IncludeAll loads every relation.
+	IncludeAll = IncludeActor | IncludeScenes | IncludeQuotes
+)
+
+// CharacterDetail is a character along with the actor who plays them, the
+// scenes they appear in and the things they say.
+//
+// Relations that weren't requested are left nil.
+type CharacterDetail struct {
+	Character
+
+	Actor  *Actor
+	Scenes []*Scene
+	Quotes []*Quote
+}
+
+// characterActor is a character joined with their actor.
+type characterActor struct {
+	Character
+	Actor Actor `db:"actor"`
+}
+
+// GetDetail loads a character and the relations selected by include.
+//
+// It uses one query for the character and actor, plus one for scenes and one
+// for quotes when they're included.
+//
+// If no character is found, GetDetail returns a nil CharacterDetail and no
+// error.
+func (cs *CharacterStore) GetDetail(ctx context.Context, id int64, include Include) (*CharacterDetail, error) {
+	var d CharacterDetail
+	var err error
+
+	if include&IncludeActor != 0 {
+		var row characterActor
+		err = cs.dbx.GetContext(ctx, &row, cs.dialect.Rebind(`SELECT c.id, c.actor_id, c.name, a.id AS "actor.id", a.name AS "actor.name" FROM characters c JOIN actors a ON a.id = c.actor_id WHERE c.id = ?`), id)
+		d.Character = row.Character
+		d.Actor = &row.Actor
+	} else {
+		err = cs.dbx.GetContext(ctx, &d.Character, cs.dialect.Rebind(`SELECT id, actor_id, name FROM characters WHERE id = ?`), id)
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get character detail: %w", err)
+	}
+
+	if include&IncludeScenes != 0 {
+		d.Scenes = []*Scene{}
+		err = cs.dbx.SelectContext(ctx, &d.Scenes, cs.dialect.Rebind(`SELECT s.id, s.name FROM scenes s JOIN scene_characters sc ON sc.scene_id = s.id WHERE sc.character_id = ? ORDER BY s.id`), id)
+		if err != nil {
+			return nil, fmt.Errorf("get character detail: %w", err)
+		}
+	}
+
+	if include&IncludeQuotes != 0 {
+		d.Quotes = []*Quote{}
+		err = cs.dbx.SelectContext(ctx, &d.Quotes, cs.dialect.Rebind(`SELECT id, character_id, scene_id, text FROM quotes WHERE character_id = ? ORDER BY id`), id)
+		if err != nil {
+			return nil, fmt.Errorf("get character detail: %w", err)
+		}
+	}
+
+	return &d, nil
+}
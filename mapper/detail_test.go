@@ -0,0 +1,60 @@
+package mapper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pboyd/godbmodels/common"
+	"github.com/pboyd/godbmodels/common/repotest"
+)
+
+func TestGetDetail(t *testing.T) {
+	repotest.TestGetDetail(t, func(t testing.TB) repotest.DetailFunc {
+		cs := NewCharacterStore(common.TestTx(t))
+		return func(ctx context.Context, id int64, include repotest.Include) (*repotest.Detail, error) {
+			d, err := cs.GetDetail(ctx, id, toInclude(include))
+			if d == nil || err != nil {
+				return nil, err
+			}
+			return toDetail(d), nil
+		}
+	})
+}
+
+func toInclude(include repotest.Include) Include {
+	var i Include
+	if include.Actor {
+		i |= IncludeActor
+	}
+	if include.Scenes {
+		i |= IncludeScenes
+	}
+	if include.Quotes {
+		i |= IncludeQuotes
+	}
+	return i
+}
+
+func toDetail(d *CharacterDetail) *repotest.Detail {
+	out := &repotest.Detail{Character: *toCommon(&d.Character)}
+
+	if d.Actor != nil {
+		out.Actor = &repotest.Actor{ID: d.Actor.ID, Name: d.Actor.Name}
+	}
+
+	if d.Scenes != nil {
+		out.Scenes = []repotest.Scene{}
+		for _, s := range d.Scenes {
+			out.Scenes = append(out.Scenes, repotest.Scene{ID: s.ID, Name: s.Name})
+		}
+	}
+
+	if d.Quotes != nil {
+		out.Quotes = []repotest.Quote{}
+		for _, q := range d.Quotes {
+			out.Quotes = append(out.Quotes, repotest.Quote{ID: q.ID, CharacterID: q.CharacterID})
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,79 @@
+package mapper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pboyd/godbmodels/common"
+	"github.com/pboyd/godbmodels/common/sqltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDialects(t *testing.T) {
+	for _, d := range common.Dialects {
+		d := d
+		t.Run(d.Name, func(t *testing.T) {
+			ctx := context.Background()
+			db, rec := sqltest.Open(t)
+			cs := NewCharacterStore(db, WithDialect(d))
+
+			rec.Section("Get")
+			cs.Get(ctx, 1)
+
+			rec.Section("Insert")
+			cs.Store(ctx, &Character{ActorID: 1, Name: "Sir Not-Appearing-in-this-Film"})
+
+			rec.Section("Update")
+			cs.Store(ctx, &Character{ID: 82, ActorID: 2, Name: "Sir Maybe-Appearing-in-this-Film"})
+
+			rec.Section("Delete")
+			cs.Delete(ctx, 82)
+
+			rec.Section("List")
+			cs.List(ctx, nil)
+			cs.List(ctx, &CharacterFilters{ActorID: 3, Name: "Brother", SceneNumber: 3})
+			cs.List(ctx, &CharacterFilters{ActorName: "Sandy", Name: "Knight"})
+			cs.List(ctx, &CharacterFilters{Name: "Knight", OrderBy: common.OrderByActorName, Descending: true})
+			cs.List(ctx, &CharacterFilters{ActorIDs: []int64{1, 2}, ExcludeActorIDs: []int64{3}, SceneNumbers: []int64{1, 3}, AllScenes: true, SceneFrom: 10, SceneTo: 15})
+			cs.List(ctx, &CharacterFilters{SceneNumbers: []int64{1, 3}, SceneTo: 15})
+
+			rec.Section("List Page")
+			cs.ListPage(ctx, &CharacterFilters{ActorID: 3}, common.Page{Limit: 10, Offset: 20})
+			cs.ListPage(ctx, &CharacterFilters{SceneNumber: 3}, common.Page{Limit: 10, Cursor: common.EncodeCursor(common.Cursor{Name: "Dennis", ID: 20})})
+
+			rec.Section("Count")
+			cs.Count(ctx, &CharacterFilters{ActorName: "Palin", SceneNumber: 3})
+
+			rec.Section("List With Facets")
+			cs.ListWithFacets(ctx, &CharacterFilters{Name: "Knight"})
+
+			qs := NewQuoteStore(db, WithDialect(d))
+
+			rec.Section("Quote Get")
+			qs.Get(ctx, 1)
+
+			rec.Section("Quote Insert")
+			qs.Store(ctx, &Quote{CharacterID: 1, SceneID: 3, Text: "Bring out your dead!"})
+
+			rec.Section("Quote Update")
+			qs.Store(ctx, &Quote{ID: 13, CharacterID: 1, SceneID: 3, Text: "I'm not dead."})
+
+			rec.Section("Quote Delete")
+			qs.Delete(ctx, 13)
+
+			rec.Section("Quote List")
+			qs.List(ctx, &QuoteFilters{CharacterID: 1, ActorID: 1, SceneNumber: 3, Text: "Dead"})
+
+			sqltest.Golden(t, "dialect_"+d.Name, rec.String())
+		})
+	}
+}
+
+func TestExtBindType(t *testing.T) {
+	for _, d := range common.Dialects {
+		db, _ := sqltest.Open(t)
+		dbx := newExt(db, d).(*sqlx.DB)
+		assert.Equal(t, d.Rebind("SELECT ? + ?"), dbx.Rebind("SELECT ? + ?"), d.Name)
+	}
+}
@@ -0,0 +1,234 @@
+package mapper
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pboyd/godbmodels/common"
+)
+
+// ErrNotInScene is returned when storing a quote for a character that does not
+// appear in the quote's scene.
+var ErrNotInScene = errors.New("character does not appear in the scene")
+
+// Quote is one quote from the database.
+type Quote struct {
+	ID          int64  `db:"id"`
+	CharacterID int64  `db:"character_id"`
+	SceneID     int64  `db:"scene_id"`
+	Text        string `db:"text"`
+}
+
+// Actor is one actor from the database.
+type Actor struct {
+	ID   int64  `db:"id"`
+	Name string `db:"name"`
+}
+
+// Scene is one scene from the database. The ID is the scene number.
+type Scene struct {
+	ID   int64  `db:"id"`
+	Name string `db:"name"`
+}
+
+// QuoteView is a quote along with who said it and where.
+//
+// sqlx fills in the nested structs from columns prefixed with the field's tag,
+// so "character.name" is scanned into Character.Name.
+type QuoteView struct {
+	ID   int64  `db:"id"`
+	Text string `db:"text"`
+
+	Character Character `db:"character"`
+	Actor     Actor     `db:"actor"`
+	Scene     Scene     `db:"scene"`
+}
+
+// quoteViewColumns are the columns of a QuoteView, from the tables joined by
+// quoteViewJoins.
+const quoteViewColumns = `
+	q.id,
+	q.text,
+	c.id AS "character.id",
+	c.actor_id AS "character.actor_id",
+	c.name AS "character.name",
+	a.id AS "actor.id",
+	a.name AS "actor.name",
+	s.id AS "scene.id",
+	s.name AS "scene.name"`
+
+const quoteViewJoins = `
+JOIN characters c ON c.id = q.character_id
+JOIN actors a ON a.id = c.actor_id
+JOIN scenes s ON s.id = q.scene_id`
+
+// quoteViewQuery selects the columns of a QuoteView.
+const quoteViewQuery = `SELECT` + quoteViewColumns + `
+FROM quotes q` + quoteViewJoins
+
+// QuoteStore loads and updates quotes in the database.
+type QuoteStore struct {
+	dbx     ext
+	dialect *common.Dialect
+}
+
+// NewQuoteStore creates a new QuoteStore. db can be a *sql.DB or a *sql.Tx.
+func NewQuoteStore[T DB](db T, opts ...Option) *QuoteStore {
+	o := newOptions(opts)
+	return &QuoteStore{dbx: newExt(db, o.dialect), dialect: o.dialect}
+}
+
+// Get loads a quote from the database by ID.
+//
+// If no quote is found, Get returns a nil QuoteView and no error.
+func (qs *QuoteStore) Get(ctx context.Context, id int64) (*QuoteView, error) {
+	var q QuoteView
+	err := qs.dbx.GetContext(ctx, &q, qs.dialect.Rebind(quoteViewQuery+` WHERE q.id = ?`), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	return &q, err
+}
+
+// Store saves a quote to the database. If the quote has an ID, it will be
+// updated. Otherwise, it will be inserted and the ID will be set.
+//
+// If the character does not appear in the scene, Store returns
+// ErrNotInScene. If the quote has an ID and it does not exist in the
+// database, Store returns ErrNotFound. The scene is checked in the same
+// transaction as the write.
+func (qs *QuoteStore) Store(ctx context.Context, q *Quote) error {
+	return inTx(ctx, qs.dbx, func(tx ext) error {
+		var n int
+		err := tx.GetContext(ctx, &n, qs.dialect.Rebind(`SELECT COUNT(*) FROM scene_characters WHERE scene_id = ? AND character_id = ?`), q.SceneID, q.CharacterID)
+		if err != nil {
+			return fmt.Errorf("store quote: %w", err)
+		}
+		if n == 0 {
+			return ErrNotInScene
+		}
+
+		if q.ID == 0 {
+			return qs.insert(ctx, tx, q)
+		}
+
+		return qs.update(ctx, tx, q)
+	})
+}
+
+func (qs *QuoteStore) insert(ctx context.Context, tx ext, q *Quote) error {
+	query, args, err := sqlx.Named(`INSERT INTO quotes (character_id, scene_id, text) VALUES (:character_id, :scene_id, :text)`, q)
+	if err != nil {
+		return fmt.Errorf("insert quote: %w", err)
+	}
+
+	id, err := qs.dialect.Insert(ctx, tx, query, args...)
+	if err != nil {
+		return fmt.Errorf("insert quote: %w", err)
+	}
+
+	q.ID = id
+	return nil
+}
+
+func (qs *QuoteStore) update(ctx context.Context, tx ext, q *Quote) error {
+	query, args, err := sqlx.Named(`UPDATE quotes SET character_id = :character_id, scene_id = :scene_id, text = :text WHERE id = :id`, q)
+	if err != nil {
+		return fmt.Errorf("update quote: %w", err)
+	}
+
+	res, err := tx.ExecContext(ctx, qs.dialect.Rebind(query), args...)
+	if err != nil {
+		return fmt.Errorf("update quote: %w", err)
+	}
+
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// Delete removes a quote from the database.
+//
+// If the quote does not exist in the database, Delete returns ErrNotFound.
+func (qs *QuoteStore) Delete(ctx context.Context, id int64) error {
+	res, err := qs.dbx.ExecContext(ctx, qs.dialect.Rebind(`DELETE FROM quotes WHERE id = ?`), id)
+	if err != nil {
+		return fmt.Errorf("delete quote: %w", err)
+	}
+
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// QuoteFilters are used to filter the results of a QuoteStore.List query.
+type QuoteFilters struct {
+	// CharacterID matches on the ID of the character who said the quote.
+	CharacterID int64
+
+	// ActorID matches on the ID of the actor who plays the character.
+	ActorID int64
+
+	// SceneNumber matches on the scene the quote is from.
+	SceneNumber int64
+
+	// Text does a case-insensitive partial match on the quote.
+	Text string
+}
+
+// List searches for quotes in the database, in order of ID.
+//
+// If filters is nil, all quotes are returned. Otherwise, the results are
+// filtered by the criteria in filters.
+func (qs *QuoteStore) List(ctx context.Context, filters *QuoteFilters) ([]*QuoteView, error) {
+	var args []interface{}
+	query := quoteViewQuery
+	where := []string{}
+
+	if filters != nil {
+		if filters.CharacterID != 0 {
+			where = append(where, "q.character_id = ?")
+			args = append(args, filters.CharacterID)
+		}
+
+		if filters.ActorID != 0 {
+			where = append(where, "c.actor_id = ?")
+			args = append(args, filters.ActorID)
+		}
+
+		if filters.SceneNumber != 0 {
+			where = append(where, "q.scene_id = ?")
+			args = append(args, filters.SceneNumber)
+		}
+
+		if filters.Text != "" {
+			cond, arg := qs.dialect.ContainsFold("q.text", filters.Text)
+			where = append(where, cond)
+			args = append(args, arg)
+		}
+	}
+
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY q.id"
+
+	var quotes []*QuoteView
+	err := qs.dbx.SelectContext(ctx, &quotes, qs.dialect.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("list quotes: %w", err)
+	}
+
+	return quotes, nil
+}
@@ -0,0 +1,165 @@
+package mapper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pboyd/godbmodels/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuotes(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	qs := NewQuoteStore(common.TestTx(t))
+
+	// Dennis (34) is in scene 3.
+	q := &Quote{CharacterID: 34, SceneID: 3, Text: "Help! Help! I'm being repressed!"}
+
+	// Create
+	err := qs.Store(ctx, q)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.NotZero(q.ID)
+
+	// Read
+	v, err := qs.Get(ctx, q.ID)
+	if !assert.NoError(err) || !assert.NotNil(v) {
+		return
+	}
+	assert.Equal(q.ID, v.ID)
+	assert.Equal(q.Text, v.Text)
+	assert.Equal(Character{ID: 34, ActorID: 6, Name: "Dennis"}, v.Character)
+	assert.Equal(Actor{ID: 6, Name: "Michael Palin"}, v.Actor)
+	assert.Equal(int64(3), v.Scene.ID)
+	assert.NotEmpty(v.Scene.Name)
+
+	// Update
+	q.CharacterID = 1
+	q.Text = "Bloody peasant!"
+	err = qs.Store(ctx, q)
+	if !assert.NoError(err) {
+		return
+	}
+	v, err = qs.Get(ctx, q.ID)
+	if assert.NoError(err) && assert.NotNil(v) {
+		assert.Equal("King Arthur", v.Character.Name)
+		assert.Equal("Bloody peasant!", v.Text)
+	}
+
+	// Delete
+	err = qs.Delete(ctx, q.ID)
+	if !assert.NoError(err) {
+		return
+	}
+	v, err = qs.Get(ctx, q.ID)
+	if assert.NoError(err) {
+		assert.Nil(v)
+	}
+
+	// Delete again
+	err = qs.Delete(ctx, q.ID)
+	assert.Equal(ErrNotFound, err)
+
+	// Update missing
+	err = qs.Store(ctx, q)
+	assert.Equal(ErrNotFound, err)
+}
+
+func TestStoreQuoteNotInScene(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	tx := common.TestTx(t)
+	qs := NewQuoteStore(tx)
+
+	// Dennis isn't in scene 1.
+	err := qs.Store(ctx, &Quote{CharacterID: 34, SceneID: 1, Text: "Strange women lying in ponds"})
+	assert.Equal(ErrNotInScene, err)
+
+	// Moving an existing quote is checked too.
+	err = qs.Store(ctx, &Quote{ID: 3, CharacterID: 34, SceneID: 1, Text: "Strange women lying in ponds"})
+	assert.Equal(ErrNotInScene, err)
+
+	quotes, err := qs.List(ctx, &QuoteFilters{CharacterID: 34})
+	if assert.NoError(err) && assert.Len(quotes, 1) {
+		assert.Equal(int64(3), quotes[0].Scene.ID)
+	}
+}
+
+func TestStoreQuoteDB(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	// A *sql.DB instead of a transaction, so Store starts its own.
+	qs := NewQuoteStore(common.TestDB(t))
+
+	err := qs.Store(ctx, &Quote{CharacterID: 34, SceneID: 1, Text: "Strange women lying in ponds"})
+	assert.Equal(ErrNotInScene, err)
+
+	q := &Quote{CharacterID: 34, SceneID: 3, Text: "Help! Help! I'm being repressed!"}
+	err = qs.Store(ctx, q)
+	if assert.NoError(err) {
+		v, err := qs.Get(ctx, q.ID)
+		if assert.NoError(err) && assert.NotNil(v) {
+			assert.Equal(q.Text, v.Text)
+		}
+	}
+}
+
+func TestListQuotes(t *testing.T) {
+	cases := map[string]struct {
+		filters  *QuoteFilters
+		expected []int64
+	}{
+		"Nil": {
+			expected: []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12},
+		},
+		"Character": {
+			filters:  &QuoteFilters{CharacterID: 1},
+			expected: []int64{1, 5, 9},
+		},
+		"Actor": {
+			filters:  &QuoteFilters{ActorID: 2},
+			expected: []int64{2, 6, 10},
+		},
+		"Scene": {
+			filters:  &QuoteFilters{SceneNumber: 6},
+			expected: []int64{5, 11, 12},
+		},
+		"Text": {
+			filters:  &QuoteFilters{Text: "MODEL"},
+			expected: []int64{12},
+		},
+		"ActorAndScene": {
+			filters:  &QuoteFilters{ActorID: 1, SceneNumber: 6},
+			expected: []int64{5},
+		},
+		"NoMatch": {
+			filters: &QuoteFilters{CharacterID: 1, SceneNumber: 3},
+		},
+	}
+
+	qs := NewQuoteStore(common.TestTx(t))
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			quotes, err := qs.List(context.Background(), tc.filters)
+			if !assert.NoError(err) {
+				return
+			}
+
+			var ids []int64
+			for _, q := range quotes {
+				ids = append(ids, q.ID)
+				assert.Equal(q.Character.ActorID, q.Actor.ID)
+				assert.NotEmpty(q.Character.Name)
+				assert.NotEmpty(q.Actor.Name)
+				assert.NotEmpty(q.Scene.Name)
+			}
+			assert.Equal(tc.expected, ids)
+		})
+	}
+}
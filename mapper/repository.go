@@ -0,0 +1,145 @@
+package mapper
+
+import (
+	"context"
+	"errors"
+
+	"github.com/pboyd/godbmodels/common"
+)
+
+var _ common.CharacterRepository = (*CharacterRepository)(nil)
+
+// CharacterRepository adapts a CharacterStore to the
+// common.CharacterRepository interface.
+type CharacterRepository struct {
+	cs *CharacterStore
+}
+
+// NewCharacterRepository creates a new CharacterRepository.
+func NewCharacterRepository(cs *CharacterStore) *CharacterRepository {
+	return &CharacterRepository{cs: cs}
+}
+
+// Get loads a character from the database by ID.
+func (r *CharacterRepository) Get(ctx context.Context, id int64) (*common.Character, error) {
+	c, err := r.cs.Get(ctx, id)
+	if c == nil || err != nil {
+		return nil, err
+	}
+
+	return toCommon(c), nil
+}
+
+// Store saves a character to the database.
+func (r *CharacterRepository) Store(ctx context.Context, c *common.Character) error {
+	vc := fromCommon(c)
+	err := r.cs.Store(ctx, vc)
+	if err != nil {
+		return commonError(err)
+	}
+
+	c.ID = vc.ID
+	return nil
+}
+
+// Delete removes a character from the database.
+func (r *CharacterRepository) Delete(ctx context.Context, id int64) error {
+	return commonError(r.cs.Delete(ctx, id))
+}
+
+// List searches for characters in the database.
+func (r *CharacterRepository) List(ctx context.Context, filters *common.CharacterFilters) ([]*common.Character, error) {
+	characters, err := r.cs.List(ctx, fromCommonFilters(filters))
+	if err != nil {
+		return nil, err
+	}
+
+	return toCommonList(characters), nil
+}
+
+// ListPage returns one page of characters from the database.
+func (r *CharacterRepository) ListPage(ctx context.Context, filters *common.CharacterFilters, page common.Page) (*common.CharacterPage, error) {
+	p, err := r.cs.ListPage(ctx, fromCommonFilters(filters), page)
+	if err != nil {
+		return nil, err
+	}
+
+	return &common.CharacterPage{
+		Characters: toCommonList(p.Characters),
+		HasMore:    p.HasMore,
+		NextCursor: p.NextCursor,
+	}, nil
+}
+
+// Count returns the number of characters that List would return.
+func (r *CharacterRepository) Count(ctx context.Context, filters *common.CharacterFilters) (int, error) {
+	return r.cs.Count(ctx, fromCommonFilters(filters))
+}
+
+// ListWithFacets searches for characters in the database and counts them by
+// actor and by scene.
+func (r *CharacterRepository) ListWithFacets(ctx context.Context, filters *common.CharacterFilters) (*common.CharacterList, error) {
+	l, err := r.cs.ListWithFacets(ctx, fromCommonFilters(filters))
+	if err != nil {
+		return nil, err
+	}
+
+	return &common.CharacterList{
+		Characters: toCommonList(l.Characters),
+		Facets:     l.Facets,
+	}, nil
+}
+
+func fromCommonFilters(filters *common.CharacterFilters) *CharacterFilters {
+	if filters == nil {
+		return nil
+	}
+
+	return &CharacterFilters{
+		ActorID:         filters.ActorID,
+		ActorName:       filters.ActorName,
+		Name:            filters.Name,
+		SceneNumber:     filters.SceneNumber,
+		ActorIDs:        filters.ActorIDs,
+		ExcludeActorIDs: filters.ExcludeActorIDs,
+		SceneNumbers:    filters.SceneNumbers,
+		AllScenes:       filters.AllScenes,
+		SceneFrom:       filters.SceneFrom,
+		SceneTo:         filters.SceneTo,
+		OrderBy:         filters.OrderBy,
+		Descending:      filters.Descending,
+	}
+}
+
+func toCommonList(characters []*Character) []*common.Character {
+	list := make([]*common.Character, len(characters))
+	for i, c := range characters {
+		list[i] = toCommon(c)
+	}
+
+	return list
+}
+
+func toCommon(c *Character) *common.Character {
+	return &common.Character{
+		ID:      c.ID,
+		ActorID: c.ActorID,
+		Name:    c.Name,
+	}
+}
+
+func fromCommon(c *common.Character) *Character {
+	return &Character{
+		ID:      c.ID,
+		ActorID: c.ActorID,
+		Name:    c.Name,
+	}
+}
+
+func commonError(err error) error {
+	if errors.Is(err, ErrNotFound) {
+		return common.ErrNotFound
+	}
+
+	return err
+}
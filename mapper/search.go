@@ -0,0 +1,98 @@
+package mapper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/pboyd/godbmodels/common"
+)
+
+// ErrSearchUnavailable is returned by QuoteStore.Search when the database has
+// no full-text index. The index is only created when SQLite is built with
+// FTS5, and only SQLite is supported.
+var ErrSearchUnavailable = errors.New("quote search is unavailable")
+
+// SearchResult is a quote that matched a search.
+type SearchResult struct {
+	QuoteView
+
+	// Score is the bm25 rank of the match. Lower scores are better matches.
+	Score float64 `db:"score"`
+
+	// Snippet is the part of the quote around the match, with each matching
+	// term wrapped in [brackets].
+	Snippet string `db:"snippet"`
+}
+
+// SearchOptions narrow the results of QuoteStore.Search.
+type SearchOptions struct {
+	// CharacterID matches on the ID of the character who said the quote.
+	CharacterID int64
+
+	// SceneNumber matches on the scene the quote is from.
+	SceneNumber int64
+
+	// Limit is the maximum number of results. Zero means no limit.
+	Limit int
+}
+
+const searchQuery = `SELECT` + quoteViewColumns + `,
+	bm25(quotes_fts) AS score,
+	snippet(quotes_fts, 0, '[', ']', '...', 10) AS snippet
+FROM quotes_fts
+JOIN quotes q ON q.id = quotes_fts.rowid` + quoteViewJoins + `
+WHERE quotes_fts MATCH ?`
+
+// Search finds quotes matching an FTS5 query, best matches first.
+//
+// The query supports FTS5 syntax: "phrases in quotes", prefix* searches and
+// NEAR(a b, n) groups, as well as AND, OR and NOT. A query with invalid
+// syntax returns an error.
+//
+// If the database has no full-text index, Search returns
+// ErrSearchUnavailable.
+func (qs *QuoteStore) Search(ctx context.Context, query string, opts *SearchOptions) ([]*SearchResult, error) {
+	if qs.dialect != common.SQLite {
+		return nil, ErrSearchUnavailable
+	}
+
+	var indexed bool
+	err := qs.dbx.GetContext(ctx, &indexed, `SELECT EXISTS (SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'quotes_fts')`)
+	if err != nil {
+		return nil, fmt.Errorf("search quotes: %w", err)
+	}
+	if !indexed {
+		return nil, ErrSearchUnavailable
+	}
+
+	q := searchQuery
+	args := []interface{}{query}
+
+	if opts != nil {
+		if opts.CharacterID != 0 {
+			q += " AND q.character_id = ?"
+			args = append(args, opts.CharacterID)
+		}
+
+		if opts.SceneNumber != 0 {
+			q += " AND q.scene_id = ?"
+			args = append(args, opts.SceneNumber)
+		}
+	}
+
+	q += " ORDER BY score, q.id"
+
+	if opts != nil && opts.Limit > 0 {
+		q += " LIMIT ?"
+		args = append(args, opts.Limit)
+	}
+
+	var results []*SearchResult
+	err = qs.dbx.SelectContext(ctx, &results, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("search quotes: %w", err)
+	}
+
+	return results, nil
+}
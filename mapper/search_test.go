@@ -0,0 +1,146 @@
+package mapper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pboyd/godbmodels/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearch(t *testing.T) {
+	ctx := context.Background()
+	qs := NewQuoteStore(common.TestTx(t))
+
+	_, err := qs.Search(ctx, "model", nil)
+	if err == ErrSearchUnavailable {
+		t.Skip("SQLite was built without FTS5")
+	}
+
+	cases := map[string]struct {
+		query    string
+		opts     *SearchOptions
+		expected []int64
+	}{
+		"Word": {
+			query:    "model",
+			expected: []int64{12},
+		},
+		"Phrase": {
+			query:    `"strange women"`,
+			expected: []int64{3},
+		},
+		"Prefix": {
+			query:    "shrub*",
+			expected: []int64{8},
+		},
+		"Near": {
+			query:    "NEAR(castle camelot, 2)",
+			expected: []int64{1},
+		},
+		"NotNear": {
+			query: "NEAR(arthur saxons, 2)",
+		},
+		"Or": {
+			query:    "hamster OR herring",
+			expected: []int64{6, 9},
+		},
+		"Character": {
+			query:    "ni",
+			opts:     &SearchOptions{CharacterID: 42},
+			expected: []int64{7},
+		},
+		"Scene": {
+			query:    "camelot",
+			opts:     &SearchOptions{SceneNumber: 6},
+			expected: []int64{5},
+		},
+		"Limit": {
+			query:    "ni",
+			opts:     &SearchOptions{Limit: 1},
+			expected: []int64{7},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			results, err := qs.Search(ctx, tc.query, tc.opts)
+			if !assert.NoError(err) {
+				return
+			}
+
+			var ids []int64
+			for i, r := range results {
+				ids = append(ids, r.ID)
+				assert.NotEmpty(r.Character.Name)
+				assert.NotEmpty(r.Scene.Name)
+				if i > 0 {
+					assert.LessOrEqual(results[i-1].Score, r.Score)
+				}
+			}
+			assert.ElementsMatch(tc.expected, ids)
+		})
+	}
+
+	t.Run("Rank", func(t *testing.T) {
+		assert := assert.New(t)
+
+		// Both quotes mention Camelot once, but quote 5 is shorter.
+		results, err := qs.Search(ctx, "camelot", nil)
+		if assert.NoError(err) && assert.Len(results, 2) {
+			assert.Equal(int64(5), results[0].ID)
+			assert.Equal(int64(1), results[1].ID)
+			assert.Less(results[0].Score, results[1].Score)
+		}
+	})
+
+	t.Run("Snippet", func(t *testing.T) {
+		assert := assert.New(t)
+
+		results, err := qs.Search(ctx, "model", nil)
+		if assert.NoError(err) && assert.Len(results, 1) {
+			assert.Equal("It's only a [model].", results[0].Snippet)
+			assert.Equal("Patsy", results[0].Character.Name)
+		}
+	})
+
+	t.Run("Sync", func(t *testing.T) {
+		assert := assert.New(t)
+
+		q := &Quote{CharacterID: 20, SceneID: 6, Text: "Clop clop clop."}
+		err := qs.Store(ctx, q)
+		if !assert.NoError(err) {
+			return
+		}
+
+		results, err := qs.Search(ctx, "clop", nil)
+		if assert.NoError(err) && assert.Len(results, 1) {
+			assert.Equal(q.ID, results[0].ID)
+		}
+
+		err = qs.Delete(ctx, q.ID)
+		if !assert.NoError(err) {
+			return
+		}
+
+		results, err = qs.Search(ctx, "clop", nil)
+		if assert.NoError(err) {
+			assert.Empty(results)
+		}
+	})
+
+	t.Run("Syntax", func(t *testing.T) {
+		_, err := qs.Search(ctx, `"unbalanced`, nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestSearchUnavailable(t *testing.T) {
+	// Search is only implemented for SQLite.
+	qs := NewQuoteStore(common.TestTx(t), WithDialect(common.Postgres))
+
+	_, err := qs.Search(context.Background(), "model", nil)
+	assert.Equal(t, ErrSearchUnavailable, err)
+}
@@ -1,7 +1,33 @@
 package orm
 
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
 // Actor represents the actor that plays a character.
 type Actor struct {
 	ID   int64  `gorm:"id,primary_key"`
 	Name string `gorm:"name"`
+
+	Characters []Character
+}
+
+// LoadActorCharacters loads an actor by ID, along with the characters they
+// play in order of ID.
+//
+// If no actor is found, the error wraps gorm.ErrRecordNotFound.
+func LoadActorCharacters(db *gorm.DB, id int64) (*Actor, error) {
+	var a Actor
+	err := db.
+		Preload("Characters", func(db *gorm.DB) *gorm.DB {
+			return db.Order("characters.id")
+		}).
+		First(&a, id).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load actor: %w", err)
+	}
+
+	return &a, nil
 }
@@ -0,0 +1,29 @@
+package orm
+
+import (
+	"testing"
+
+	"github.com/pboyd/godbmodels/common"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestLoadActorCharacters(t *testing.T) {
+	assert := assert.New(t)
+	db := testGorm(t, common.TestTx(t))
+
+	a, err := LoadActorCharacters(db, 3)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Equal("Eric Idle", a.Name)
+	if assert.Len(a.Characters, 8) {
+		assert.Equal("Dead Collector", a.Characters[0].Name)
+		for _, c := range a.Characters {
+			assert.Equal(a.ID, c.ActorID)
+		}
+	}
+
+	_, err = LoadActorCharacters(db, 9999)
+	assert.ErrorIs(err, gorm.ErrRecordNotFound)
+}
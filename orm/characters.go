@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/pboyd/godbmodels/common"
 	"gorm.io/gorm"
 )
 
@@ -13,7 +14,60 @@ type Character struct {
 	ActorID int64  `gorm:"actor_id"`
 	Name    string `gorm:"name"`
 
-	Actor Actor
+	Actor  Actor
+	Scenes []Scene `gorm:"many2many:scene_characters"`
+	Quotes []Quote
+}
+
+// Load selects the associations that LoadCharacter fills in.
+type Load uint
+
+const (
+	// LoadActor loads Character.Actor.
+	LoadActor Load = 1 << iota
+
+	// LoadScenes loads Character.Scenes, in scene order.
+	LoadScenes
+
+	// LoadQuotes loads Character.Quotes, in order of ID.
+	LoadQuotes
+
+	// LoadAll loads every association.
+	LoadAll = LoadActor | LoadScenes | LoadQuotes
+)
+
+// LoadCharacter loads a character by ID, along with the associations in load.
+//
+// The actor is joined into the same query. Scenes and quotes are preloaded,
+// so each one adds a query.
+//
+// If no character is found, the error wraps gorm.ErrRecordNotFound.
+func LoadCharacter(db *gorm.DB, id int64, load Load) (*Character, error) {
+	q := db
+
+	if load&LoadActor != 0 {
+		q = q.Joins("Actor")
+	}
+
+	if load&LoadScenes != 0 {
+		q = q.Preload("Scenes", func(db *gorm.DB) *gorm.DB {
+			return db.Order("scenes.id")
+		})
+	}
+
+	if load&LoadQuotes != 0 {
+		q = q.Preload("Quotes", func(db *gorm.DB) *gorm.DB {
+			return db.Order("quotes.id")
+		})
+	}
+
+	var c Character
+	err := q.First(&c, "characters.id = ?", id).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load character: %w", err)
+	}
+
+	return &c, nil
 }
 
 // CharacterFilters are used to filter the results of a List query.
@@ -29,18 +83,192 @@ type CharacterFilters struct {
 
 	// SceneNumber filters by the scene that the character appears in.
 	SceneNumber int64
+
+	// ActorIDs matches characters played by any of these actors.
+	ActorIDs []int64
+
+	// ExcludeActorIDs leaves out characters played by any of these actors.
+	ExcludeActorIDs []int64
+
+	// SceneNumbers matches characters that appear in any of these scenes,
+	// or in every one of them if AllScenes is set.
+	SceneNumbers []int64
+
+	// AllScenes makes SceneNumbers match characters that appear in every
+	// listed scene.
+	AllScenes bool
+
+	// SceneFrom and SceneTo match characters that appear in at least one
+	// scene in the range, inclusive. Either can be zero to leave that end
+	// of the range open.
+	SceneFrom int64
+	SceneTo   int64
+
+	// OrderBy sorts the results. ListCharacters defaults to
+	// common.OrderByID and ListCharactersPage defaults to
+	// common.OrderByName.
+	OrderBy common.OrderBy
+
+	// Descending reverses the order.
+	Descending bool
 }
 
 // ListCharacters searches for characters in the database.
 //
-// If filters is nil, all characters are returned. Otherwise, the results are
-// filtered by the criteria in filters.
+// If filters is nil, all characters are returned, in order of ID. Otherwise,
+// the results are filtered and sorted by the criteria in filters. If
+// filters.OrderBy is invalid, the error wraps common.ErrInvalidOrder.
 func ListCharacters(db *gorm.DB, filters *CharacterFilters) ([]*Character, error) {
-	q := db
+	orderBy, err := filters.orderClause(common.OrderByID)
+	if err != nil {
+		return nil, err
+	}
+
+	var characters []*Character
+	err = db.Scopes(filterCharacters(filters)).Order(orderBy).Find(&characters).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list characters: %w", err)
+	}
+
+	return characters, nil
+}
+
+// CharacterPage is one page of characters from ListCharactersPage.
+type CharacterPage struct {
+	Characters []*Character
+
+	// HasMore is true if there are characters after this page.
+	HasMore bool
+
+	// NextCursor continues from the last character on this page. It's empty
+	// if HasMore is false.
+	NextCursor string
+}
+
+// ListCharactersPage is like ListCharacters, but returns one page of the
+// results. The default order is by name.
+//
+// If page is invalid, the error wraps common.ErrInvalidPage or
+// common.ErrInvalidCursor.
+func ListCharactersPage(db *gorm.DB, filters *CharacterFilters, page common.Page) (*CharacterPage, error) {
+	orderBy, err := filters.orderClause(common.OrderByName)
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := page.After(filters.order(common.OrderByName))
+	if err != nil {
+		return nil, err
+	}
+
+	// One extra row is fetched to find out if there's another page.
+	q := db.
+		Scopes(filterCharacters(filters)).
+		Order(orderBy).
+		Limit(page.Limit + 1).
+		Offset(page.Offset)
+
+	if after != nil {
+		q = q.Where("(characters.name > ? OR (characters.name = ? AND characters.id > ?))", after.Name, after.Name, after.ID)
+	}
+
+	var characters []*Character
+	err = q.Find(&characters).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list characters: %w", err)
+	}
+
+	p := &CharacterPage{}
+	p.Characters, p.HasMore, p.NextCursor = common.TrimPage(characters, page.Limit, func(c *Character) common.Cursor {
+		return common.Cursor{Name: c.Name, ID: c.ID}
+	})
+	return p, nil
+}
+
+// CountCharacters returns the number of characters that ListCharacters would
+// return for filters. filters.OrderBy and Descending are ignored.
+func CountCharacters(db *gorm.DB, filters *CharacterFilters) (int, error) {
+	var n int64
+	err := db.Model(&Character{}).Scopes(filterCharacters(filters)).Count(&n).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count characters: %w", err)
+	}
+
+	return int(n), nil
+}
+
+// CharacterList is a list of characters from ListCharactersWithFacets.
+type CharacterList struct {
+	Characters []*Character
+	Facets     common.Facets
+}
+
+// ListCharactersWithFacets is like ListCharacters, but also counts the
+// matching characters by actor and by scene. The counts are grouped by the
+// database.
+func ListCharactersWithFacets(db *gorm.DB, filters *CharacterFilters) (*CharacterList, error) {
+	characters, err := ListCharacters(db, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &CharacterList{Characters: characters}
+
+	// The columns are named for common.FacetCount's fields.
+	err = db.Model(&Character{}).
+		Scopes(filterCharacters(filters)).
+		Select("characters.actor_id AS id, COUNT(*) AS count").
+		Group("characters.actor_id").
+		Order("characters.actor_id").
+		Scan(&l.Facets.Actors).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count characters by actor: %w", err)
+	}
+
+	err = db.Model(&Character{}).
+		Scopes(filterCharacters(filters)).
+		Joins("INNER JOIN scene_characters fsc ON fsc.character_id=characters.id").
+		Select("fsc.scene_id AS id, COUNT(*) AS count").
+		Group("fsc.scene_id").
+		Order("fsc.scene_id").
+		Scan(&l.Facets.Scenes).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count characters by scene: %w", err)
+	}
+
+	return l, nil
+}
+
+// order returns the order the results are sorted in, which is def unless
+// filters says otherwise.
+func (filters *CharacterFilters) order(def common.OrderBy) (common.OrderBy, bool) {
+	if filters == nil {
+		return def, false
+	}
+	return filters.OrderBy.Or(def), filters.Descending
+}
+
+// orderClause returns the ORDER BY expressions for filters.
+func (filters *CharacterFilters) orderClause(def common.OrderBy) (string, error) {
+	order, desc := filters.order(def)
+	clause, err := common.OrderClause("characters", order, desc)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Join(clause, ", "), nil
+}
+
+// filterCharacters returns a scope that applies filters to a query on
+// characters.
+func filterCharacters(filters *CharacterFilters) func(*gorm.DB) *gorm.DB {
+	return func(q *gorm.DB) *gorm.DB {
+		if filters == nil {
+			return q
+		}
 
-	if filters != nil {
 		if filters.ActorID != 0 {
-			q = q.Where("actor_id = ?", filters.ActorID)
+			q = q.Where("characters.actor_id = ?", filters.ActorID)
 		} else if filters.ActorName != "" {
 			q = q.
 				Joins("Actor").
@@ -48,7 +276,7 @@ func ListCharacters(db *gorm.DB, filters *CharacterFilters) ([]*Character, error
 		}
 
 		if filters.Name != "" {
-			q = q.Where("LOWER(name) LIKE ?", "%"+strings.ToLower(filters.Name)+"%")
+			q = q.Where("LOWER(characters.name) LIKE ?", "%"+strings.ToLower(filters.Name)+"%")
 		}
 
 		if filters.SceneNumber != 0 {
@@ -56,13 +284,47 @@ func ListCharacters(db *gorm.DB, filters *CharacterFilters) ([]*Character, error
 				Joins("INNER JOIN scene_characters ON scene_characters.character_id=characters.id").
 				Where("scene_characters.scene_id = ?", filters.SceneNumber)
 		}
-	}
 
-	var characters []*Character
-	err := q.Find(&characters).Error
-	if err != nil {
-		return nil, fmt.Errorf("failed to list characters: %w", err)
-	}
+		// GORM expands a slice to one placeholder per ID, which runs into
+		// SQLite's limit on parameters, so the lists are passed as JSON.
+		if len(filters.ActorIDs) > 0 {
+			q = q.Where(common.SQLite.InIDs("characters.actor_id", filters.ActorIDs))
+		}
 
-	return characters, nil
+		if len(filters.ExcludeActorIDs) > 0 {
+			cond, arg := common.SQLite.InIDs("characters.actor_id", filters.ExcludeActorIDs)
+			q = q.Where("NOT "+cond, arg)
+		}
+
+		if len(filters.SceneNumbers) > 0 {
+			cond, arg := common.SQLite.InIDs("ssc.scene_id", filters.SceneNumbers)
+			sub := q.Session(&gorm.Session{NewDB: true}).
+				Table("scene_characters ssc").
+				Select("ssc.character_id").
+				Where(cond, arg)
+			if filters.AllScenes {
+				// The character has to be in as many of the scenes as there
+				// are scenes in the list.
+				sub = sub.
+					Group("ssc.character_id").
+					Having("COUNT(*) = ?", len(common.DistinctIDs(filters.SceneNumbers)))
+			}
+			q = q.Where("characters.id IN (?)", sub)
+		}
+
+		if filters.SceneFrom != 0 || filters.SceneTo != 0 {
+			sub := q.Session(&gorm.Session{NewDB: true}).
+				Table("scene_characters rsc").
+				Select("rsc.character_id")
+			if filters.SceneFrom != 0 {
+				sub = sub.Where("rsc.scene_id >= ?", filters.SceneFrom)
+			}
+			if filters.SceneTo != 0 {
+				sub = sub.Where("rsc.scene_id <= ?", filters.SceneTo)
+			}
+			q = q.Where("characters.id IN (?)", sub)
+		}
+
+		return q
+	}
 }
@@ -1,13 +1,12 @@
 package orm
 
 import (
-	"database/sql"
-
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
-// Open returns a gorm.DB instance for the given sql.DB sqlite instance.
-func Open(sqlDB *sql.DB) (*gorm.DB, error) {
-	return gorm.Open(sqlite.Dialector{Conn: sqlDB}, &gorm.Config{})
+// Open returns a gorm.DB instance for the given sqlite connection, which can
+// be a *sql.DB or a *sql.Tx.
+func Open(conn gorm.ConnPool) (*gorm.DB, error) {
+	return gorm.Open(sqlite.Dialector{Conn: conn}, &gorm.Config{})
 }
@@ -0,0 +1,12 @@
+package orm
+
+// Quote is a line spoken by a character in a scene.
+type Quote struct {
+	ID          int64  `gorm:"id,primaryKey"`
+	CharacterID int64  `gorm:"character_id"`
+	SceneID     int64  `gorm:"scene_id"`
+	Text        string `gorm:"text"`
+
+	Character Character
+	Scene     Scene
+}
@@ -0,0 +1,165 @@
+package orm
+
+import (
+	"context"
+	"errors"
+
+	"github.com/pboyd/godbmodels/common"
+	"gorm.io/gorm"
+)
+
+var _ common.CharacterRepository = (*CharacterRepository)(nil)
+
+// CharacterRepository adapts GORM to the common.CharacterRepository
+// interface.
+type CharacterRepository struct {
+	db *gorm.DB
+}
+
+// NewCharacterRepository creates a new CharacterRepository.
+func NewCharacterRepository(db *gorm.DB) *CharacterRepository {
+	return &CharacterRepository{db: db}
+}
+
+// Get loads a character from the database by ID.
+func (r *CharacterRepository) Get(ctx context.Context, id int64) (*common.Character, error) {
+	var c Character
+	err := r.db.WithContext(ctx).First(&c, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toCommon(&c), nil
+}
+
+// Store saves a character to the database.
+//
+// Updates don't use gorm's Save, because Save inserts the record when the
+// update doesn't match any rows.
+func (r *CharacterRepository) Store(ctx context.Context, c *common.Character) error {
+	oc := Character{
+		ID:      c.ID,
+		ActorID: c.ActorID,
+		Name:    c.Name,
+	}
+
+	if c.ID == 0 {
+		err := r.db.WithContext(ctx).Create(&oc).Error
+		if err != nil {
+			return err
+		}
+
+		c.ID = oc.ID
+		return nil
+	}
+
+	res := r.db.WithContext(ctx).
+		Model(&Character{ID: c.ID}).
+		Select("actor_id", "name").
+		Updates(&oc)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return common.ErrNotFound
+	}
+
+	return nil
+}
+
+// Delete removes a character from the database.
+func (r *CharacterRepository) Delete(ctx context.Context, id int64) error {
+	res := r.db.WithContext(ctx).Delete(&Character{}, id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return common.ErrNotFound
+	}
+
+	return nil
+}
+
+// List searches for characters in the database.
+func (r *CharacterRepository) List(ctx context.Context, filters *common.CharacterFilters) ([]*common.Character, error) {
+	characters, err := ListCharacters(r.db.WithContext(ctx), fromCommonFilters(filters))
+	if err != nil {
+		return nil, err
+	}
+
+	return toCommonList(characters), nil
+}
+
+// ListPage returns one page of characters from the database.
+func (r *CharacterRepository) ListPage(ctx context.Context, filters *common.CharacterFilters, page common.Page) (*common.CharacterPage, error) {
+	p, err := ListCharactersPage(r.db.WithContext(ctx), fromCommonFilters(filters), page)
+	if err != nil {
+		return nil, err
+	}
+
+	return &common.CharacterPage{
+		Characters: toCommonList(p.Characters),
+		HasMore:    p.HasMore,
+		NextCursor: p.NextCursor,
+	}, nil
+}
+
+// Count returns the number of characters that List would return.
+func (r *CharacterRepository) Count(ctx context.Context, filters *common.CharacterFilters) (int, error) {
+	return CountCharacters(r.db.WithContext(ctx), fromCommonFilters(filters))
+}
+
+// ListWithFacets searches for characters in the database and counts them by
+// actor and by scene.
+func (r *CharacterRepository) ListWithFacets(ctx context.Context, filters *common.CharacterFilters) (*common.CharacterList, error) {
+	l, err := ListCharactersWithFacets(r.db.WithContext(ctx), fromCommonFilters(filters))
+	if err != nil {
+		return nil, err
+	}
+
+	return &common.CharacterList{
+		Characters: toCommonList(l.Characters),
+		Facets:     l.Facets,
+	}, nil
+}
+
+func fromCommonFilters(filters *common.CharacterFilters) *CharacterFilters {
+	if filters == nil {
+		return nil
+	}
+
+	return &CharacterFilters{
+		ActorID:         filters.ActorID,
+		ActorName:       filters.ActorName,
+		Name:            filters.Name,
+		SceneNumber:     filters.SceneNumber,
+		ActorIDs:        filters.ActorIDs,
+		ExcludeActorIDs: filters.ExcludeActorIDs,
+		SceneNumbers:    filters.SceneNumbers,
+		AllScenes:       filters.AllScenes,
+		SceneFrom:       filters.SceneFrom,
+		SceneTo:         filters.SceneTo,
+		OrderBy:         filters.OrderBy,
+		Descending:      filters.Descending,
+	}
+}
+
+func toCommonList(characters []*Character) []*common.Character {
+	list := make([]*common.Character, len(characters))
+	for i, c := range characters {
+		list[i] = toCommon(c)
+	}
+
+	return list
+}
+
+func toCommon(c *Character) *common.Character {
+	return &common.Character{
+		ID:      c.ID,
+		ActorID: c.ActorID,
+		Name:    c.Name,
+	}
+}
@@ -0,0 +1,30 @@
+package orm
+
+import (
+	"testing"
+
+	"github.com/pboyd/godbmodels/common"
+	"github.com/pboyd/godbmodels/common/repotest"
+	"gorm.io/gorm"
+)
+
+func TestCharacterRepository(t *testing.T) {
+	repotest.TestCharacterRepository(t, func(t testing.TB) common.CharacterRepository {
+		return NewCharacterRepository(testGorm(t, common.TestTx(t)))
+	})
+}
+
+func BenchmarkCharacterRepository(b *testing.B) {
+	repotest.BenchmarkCharacterRepository(b, func(t testing.TB) common.CharacterRepository {
+		return NewCharacterRepository(testGorm(t, common.TestDB(t)))
+	})
+}
+
+func testGorm(t testing.TB, conn gorm.ConnPool) *gorm.DB {
+	db, err := Open(conn)
+	if err != nil {
+		t.Fatalf("Error opening gorm: %s", err)
+	}
+
+	return db
+}
@@ -0,0 +1,10 @@
+package orm
+
+// Scene is one scene from the database. The ID is the scene number.
+type Scene struct {
+	ID   int64  `gorm:"id,primaryKey"`
+	Name string `gorm:"name"`
+
+	Characters []Character `gorm:"many2many:scene_characters"`
+	Quotes     []Quote
+}
@@ -0,0 +1,119 @@
+package orm
+
+import (
+	"testing"
+
+	"github.com/pboyd/godbmodels/common"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestSceneAssociations(t *testing.T) {
+	tx := common.TestTx(t)
+	db := testGorm(t, tx)
+
+	// King Arthur isn't in scenes 2 or 9.
+	const arthur = 1
+	original := []int64{1, 3, 4, 5, 6, 7, 8, 11, 12, 17, 18, 19, 20, 21, 22, 23}
+
+	scenes := func(t *testing.T, ids ...int64) []Scene {
+		var s []Scene
+		err := db.Order("id").Find(&s, ids).Error
+		if err != nil {
+			t.Fatalf("Error loading scenes: %s", err)
+		}
+		return s
+	}
+
+	cases := map[string]struct {
+		edit     func(t *testing.T, a *gorm.Association) error
+		expected []int64
+	}{
+		"Append": {
+			edit: func(t *testing.T, a *gorm.Association) error {
+				return a.Append(scenes(t, 2, 9))
+			},
+			expected: []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 11, 12, 17, 18, 19, 20, 21, 22, 23},
+		},
+		"AppendExisting": {
+			edit: func(t *testing.T, a *gorm.Association) error {
+				return a.Append(scenes(t, 1))
+			},
+			expected: original,
+		},
+		"Replace": {
+			edit: func(t *testing.T, a *gorm.Association) error {
+				return a.Replace(scenes(t, 2, 3))
+			},
+			expected: []int64{2, 3},
+		},
+		"Delete": {
+			edit: func(t *testing.T, a *gorm.Association) error {
+				return a.Delete(scenes(t, 1, 23))
+			},
+			expected: original[1 : len(original)-1],
+		},
+		"Clear": {
+			edit: func(t *testing.T, a *gorm.Association) error {
+				return a.Clear()
+			},
+			expected: []int64{},
+		},
+	}
+
+	others := countOthers(t, db, arthur)
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			common.TestSavepoint(t, tx)
+
+			a := db.Model(&Character{ID: arthur}).Association("Scenes")
+			err := tc.edit(t, a)
+			if !assert.NoError(err) {
+				return
+			}
+
+			assert.Equal(tc.expected, sceneCharacters(t, db, arthur))
+
+			// Other characters' rows are untouched.
+			assert.Equal(others, countOthers(t, db, arthur))
+
+			// The scenes themselves are never deleted.
+			var n int64
+			err = db.Model(&Scene{}).Count(&n).Error
+			if assert.NoError(err) {
+				assert.Equal(int64(23), n)
+			}
+		})
+	}
+}
+
+// sceneCharacters reads the scenes a character is in directly from
+// scene_characters.
+func sceneCharacters(t *testing.T, db *gorm.DB, characterID int64) []int64 {
+	t.Helper()
+
+	ids := []int64{}
+	err := db.Table("scene_characters").
+		Where("character_id = ?", characterID).
+		Order("scene_id").
+		Pluck("scene_id", &ids).Error
+	if err != nil {
+		t.Fatalf("Error reading scene_characters: %s", err)
+	}
+	return ids
+}
+
+// countOthers counts the scene_characters rows that belong to other
+// characters.
+func countOthers(t *testing.T, db *gorm.DB, characterID int64) int64 {
+	t.Helper()
+
+	var n int64
+	err := db.Table("scene_characters").Where("character_id <> ?", characterID).Count(&n).Error
+	if err != nil {
+		t.Fatalf("Error counting scene_characters: %s", err)
+	}
+	return n
+}
@@ -0,0 +1,45 @@
+package orm
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/pboyd/godbmodels/common"
+	"github.com/pboyd/godbmodels/common/introspect"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm/schema"
+)
+
+// TestSchema checks that every model matches the table GORM maps it to.
+func TestSchema(t *testing.T) {
+	expected, err := introspect.Expected(context.Background())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	db := testGorm(t, common.TestTx(t))
+	for _, model := range []interface{}{&Actor{}, &Character{}, &Scene{}, &Quote{}} {
+		s, err := schema.Parse(model, &sync.Map{}, db.NamingStrategy)
+		if !assert.NoError(t, err) {
+			continue
+		}
+
+		table := expected.Table(s.Table)
+		if !assert.NotNil(t, table, "no table for %s", s.Name) {
+			continue
+		}
+
+		var fields []introspect.Field
+		for _, f := range s.Fields {
+			if f.DBName == "" {
+				continue
+			}
+			fields = append(fields, introspect.Field{Name: f.Name, Column: f.DBName, Type: f.FieldType})
+		}
+
+		for _, d := range table.CheckFields(fields) {
+			t.Errorf("%s: %s", s.Name, d)
+		}
+	}
+}
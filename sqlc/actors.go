@@ -0,0 +1,70 @@
+package sqlc
+
+import (
+	"context"
+)
+
+// StoreActor saves an actor to the database. If the actor has an ID, it will
+// be updated. Otherwise, it will be inserted and the ID will be set.
+//
+// If the actor has an ID and it does not exist in the database, StoreActor
+// returns ErrNotFound.
+func (q *Queries) StoreActor(ctx context.Context, a *Actor) error {
+	if a.ID == 0 {
+		id, err := q.insertActor(ctx, a.Name)
+		if err != nil {
+			return err
+		}
+
+		a.ID = id
+		return nil
+	}
+
+	rows, err := q.updateActor(ctx, updateActorParams{
+		ID:   a.ID,
+		Name: a.Name,
+	})
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// ActorFilters are used to filter the results of a ListActors query.
+type ActorFilters struct {
+	// Name does a case-insensitive partial match on the actor name.
+	Name string
+}
+
+// ListActors searches for actors in the database, in order of ID.
+//
+// If filters is nil, all actors are returned. Otherwise, the results are
+// filtered by the criteria in filters.
+func (q *Queries) ListActors(ctx context.Context, filters *ActorFilters) ([]Actor, error) {
+	if filters != nil && filters.Name != "" {
+		return q.listActorsByName(ctx, filters.Name)
+	}
+
+	return q.listAllActors(ctx)
+}
+
+// DeleteActor removes an actor from the database. It fails if the actor still
+// plays any characters.
+//
+// If the actor does not exist in the database, DeleteActor returns
+// ErrNotFound.
+func (q *Queries) DeleteActor(ctx context.Context, id int64) error {
+	rows, err := q.deleteActor(ctx, id)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: actors.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const getActor = `-- name: GetActor :one
+SELECT id, name FROM actors WHERE id = ?
+`
+
+// GetActor loads an actor from the database by ID.
+func (q *Queries) GetActor(ctx context.Context, id int64) (Actor, error) {
+	row := q.db.QueryRowContext(ctx, getActor, id)
+	var i Actor
+	err := row.Scan(&i.ID, &i.Name)
+	return i, err
+}
+
+const deleteActor = `-- name: deleteActor :execrows
+DELETE FROM actors WHERE id = ?
+`
+
+// deleteActor removes an actor from the database.
+func (q *Queries) deleteActor(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteActor, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const insertActor = `-- name: insertActor :one
+INSERT INTO actors (name) VALUES (?) RETURNING id
+`
+
+// insertActor creates a new actor record.
+func (q *Queries) insertActor(ctx context.Context, name string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, insertActor, name)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const listActorsByName = `-- name: listActorsByName :many
+SELECT id, name FROM actors WHERE LOWER(name) LIKE '%' || LOWER(?) || '%' ORDER BY id
+`
+
+// listActorsByName returns all actors with a name matching the given name.
+func (q *Queries) listActorsByName(ctx context.Context, lower string) ([]Actor, error) {
+	rows, err := q.db.QueryContext(ctx, listActorsByName, lower)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Actor
+	for rows.Next() {
+		var i Actor
+		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAllActors = `-- name: listAllActors :many
+SELECT id, name FROM actors ORDER BY id
+`
+
+// listAllActors returns all actors.
+func (q *Queries) listAllActors(ctx context.Context) ([]Actor, error) {
+	rows, err := q.db.QueryContext(ctx, listAllActors)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Actor
+	for rows.Next() {
+		var i Actor
+		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateActor = `-- name: updateActor :execrows
+UPDATE actors SET name = ? WHERE id = ?
+`
+
+type updateActorParams struct {
+	Name string
+	ID   int64
+}
+
+// updateActor updates an actor's name.
+func (q *Queries) updateActor(ctx context.Context, arg updateActorParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateActor, arg.Name, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
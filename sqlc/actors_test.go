@@ -0,0 +1,79 @@
+package sqlc
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/pboyd/godbmodels/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActors(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	q := New(common.TestTx(t))
+	a := Actor{Name: "Neil Innes"}
+
+	// Create
+	err := q.StoreActor(ctx, &a)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.NotZero(a.ID)
+
+	// Read
+	a2, err := q.GetActor(ctx, a.ID)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Equal(a, a2)
+
+	// Update
+	a.Name = "Neil James Innes"
+	err = q.StoreActor(ctx, &a)
+	if !assert.NoError(err) {
+		return
+	}
+	a2, err = q.GetActor(ctx, a.ID)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Equal(a, a2)
+
+	// Delete
+	err = q.DeleteActor(ctx, a.ID)
+	if !assert.NoError(err) {
+		return
+	}
+	_, err = q.GetActor(ctx, a.ID)
+	assert.ErrorIs(err, sql.ErrNoRows)
+
+	// Update missing
+	err = q.StoreActor(ctx, &a)
+	assert.ErrorIs(err, ErrNotFound)
+
+	// Delete missing
+	err = q.DeleteActor(ctx, a.ID)
+	assert.ErrorIs(err, ErrNotFound)
+
+	// An actor with characters can't be deleted.
+	err = q.DeleteActor(ctx, 3)
+	assert.Error(err)
+}
+
+func TestListActors(t *testing.T) {
+	assert := assert.New(t)
+	q := New(common.TestTx(t))
+
+	actors, err := q.ListActors(context.Background(), nil)
+	if assert.NoError(err) && assert.Len(actors, 35) {
+		assert.Equal(Actor{ID: 1, Name: "Graham Chapman"}, actors[0])
+	}
+
+	actors, err = q.ListActors(context.Background(), &ActorFilters{Name: "TERRY"})
+	if assert.NoError(err) {
+		assert.Equal([]Actor{{ID: 4, Name: "Terry Gilliam"}, {ID: 5, Name: "Terry Jones"}}, actors)
+	}
+}
@@ -2,11 +2,22 @@ package sqlc
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/pboyd/godbmodels/common"
 )
 
+// ErrNotFound is returned when updating or deleting a record that does not
+// exist in the database.
+var ErrNotFound = errors.New("not found")
+
 // StoreCharacter saves a character to the database. If the character has an
 // ID, it will be updated. Otherwise, it will be inserted and the ID will be
 // set.
+//
+// If the character has an ID and it does not exist in the database,
+// StoreCharacter returns ErrNotFound.
 func (q *Queries) StoreCharacter(ctx context.Context, c *Character) error {
 	if c.ID == 0 {
 		id, err := q.insertCharacter(ctx, insertCharacterParams{
@@ -21,11 +32,35 @@ func (q *Queries) StoreCharacter(ctx context.Context, c *Character) error {
 		return nil
 	}
 
-	return q.updateCharacter(ctx, updateCharacterParams{
+	rows, err := q.updateCharacter(ctx, updateCharacterParams{
 		ID:      c.ID,
 		ActorID: c.ActorID,
 		Name:    c.Name,
 	})
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteCharacter removes a character from the database.
+//
+// If the character does not exist in the database, DeleteCharacter returns
+// ErrNotFound.
+func (q *Queries) DeleteCharacter(ctx context.Context, id int64) error {
+	rows, err := q.deleteCharacter(ctx, id)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrNotFound
+	}
+
+	return nil
 }
 
 // CharacterFilters are used to filter the results of a List query.
@@ -41,24 +76,234 @@ type CharacterFilters struct {
 
 	// SceneNumber filters by the scene that the character appears in.
 	SceneNumber int64
+
+	// ActorIDs matches characters played by any of these actors.
+	ActorIDs []int64
+
+	// ExcludeActorIDs leaves out characters played by any of these actors.
+	ExcludeActorIDs []int64
+
+	// SceneNumbers matches characters that appear in any of these scenes,
+	// or in every one of them if AllScenes is set.
+	SceneNumbers []int64
+
+	// AllScenes makes SceneNumbers match characters that appear in every
+	// listed scene.
+	AllScenes bool
+
+	// SceneFrom and SceneTo match characters that appear in at least one
+	// scene in the range, inclusive. Either can be zero to leave that end
+	// of the range open.
+	SceneFrom int64
+	SceneTo   int64
+
+	// OrderBy sorts the results. ListCharacters defaults to
+	// common.OrderByID and ListCharactersPage defaults to
+	// common.OrderByName.
+	OrderBy common.OrderBy
+
+	// Descending reverses the order.
+	Descending bool
 }
 
 // ListCharacters searches for characters in the database.
 //
-// If filters is nil, all characters are returned. Otherwise, the results are
-// filtered by the criteria in filters. Only one filter option can be used at
-// a time.
+// If filters is nil, all characters are returned, in order of ID. Otherwise,
+// the results are filtered and sorted by the criteria in filters. ActorName
+// is ignored when ActorID is set. If filters.OrderBy is invalid, the error
+// wraps common.ErrInvalidOrder.
 func (q *Queries) ListCharacters(ctx context.Context, filters *CharacterFilters) ([]Character, error) {
-	switch {
-	case filters.ActorID != 0:
-		return q.listCharactersByActor(ctx, filters.ActorID)
-	case filters.ActorName != "":
-		return q.listCharactersByActorName(ctx, filters.ActorName)
-	case filters.Name != "":
-		return q.listCharactersByName(ctx, filters.Name)
-	case filters.SceneNumber != 0:
-		return q.listCharactersByScene(ctx, filters.SceneNumber)
-	default:
-		return q.listAllCharacters(ctx)
+	params, err := listParams(filters, common.OrderByID)
+	if err != nil {
+		return nil, err
+	}
+	return q.listCharacters(ctx, params)
+}
+
+// CharacterPage is one page of characters from ListCharactersPage.
+type CharacterPage struct {
+	Characters []*Character
+
+	// HasMore is true if there are characters after this page.
+	HasMore bool
+
+	// NextCursor continues from the last character on this page. It's empty
+	// if HasMore is false.
+	NextCursor string
+}
+
+// ListCharactersPage is like ListCharacters, but returns one page of the
+// results. The default order is by name.
+//
+// If page is invalid, the error wraps common.ErrInvalidPage or
+// common.ErrInvalidCursor.
+func (q *Queries) ListCharactersPage(ctx context.Context, filters *CharacterFilters, page common.Page) (*CharacterPage, error) {
+	params, err := listParams(filters, common.OrderByName)
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := page.After(common.OrderBy(params.OrderBy), params.Descending)
+	if err != nil {
+		return nil, err
+	}
+
+	// One extra row is fetched to find out if there's another page.
+	params.Limit = int64(page.Limit) + 1
+	params.Offset = int64(page.Offset)
+	if after != nil {
+		params.AfterName = sql.NullString{String: after.Name, Valid: true}
+		params.AfterID = sql.NullInt64{Int64: after.ID, Valid: true}
+	}
+
+	characters, err := q.listCharacters(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*Character, len(characters))
+	for i := range characters {
+		list[i] = &characters[i]
+	}
+
+	p := &CharacterPage{}
+	p.Characters, p.HasMore, p.NextCursor = common.TrimPage(list, page.Limit, func(c *Character) common.Cursor {
+		return common.Cursor{Name: c.Name, ID: c.ID}
+	})
+	return p, nil
+}
+
+// CountCharacters returns the number of characters that ListCharacters would
+// return for filters. filters.OrderBy and Descending are ignored.
+func (q *Queries) CountCharacters(ctx context.Context, filters *CharacterFilters) (int, error) {
+	rows, err := q.countCharacters(ctx, filterParams(filters))
+	if err != nil {
+		return 0, err
+	}
+
+	for _, row := range rows {
+		if row.Facet == "total" {
+			return int(row.Count), nil
+		}
+	}
+	return 0, nil
+}
+
+// CharacterList is a list of characters from ListCharactersWithFacets.
+type CharacterList struct {
+	Characters []*Character
+	Facets     common.Facets
+}
+
+// ListCharactersWithFacets is like ListCharacters, but also counts the
+// matching characters by actor and by scene. The counts are grouped by the
+// database.
+func (q *Queries) ListCharactersWithFacets(ctx context.Context, filters *CharacterFilters) (*CharacterList, error) {
+	characters, err := q.ListCharacters(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &CharacterList{Characters: make([]*Character, len(characters))}
+	for i := range characters {
+		l.Characters[i] = &characters[i]
+	}
+
+	params := filterParams(filters)
+	params.Facets = true
+
+	rows, err := q.countCharacters(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		switch row.Facet {
+		case "actor":
+			l.Facets.Actors = append(l.Facets.Actors, common.FacetCount{ID: row.ID, Count: int(row.Count)})
+		case "scene":
+			l.Facets.Scenes = append(l.Facets.Scenes, common.FacetCount{ID: row.ID, Count: int(row.Count)})
+		}
+	}
+
+	return l, nil
+}
+
+// filterParams converts filters to query parameters.
+func filterParams(filters *CharacterFilters) countCharactersParams {
+	var params countCharactersParams
+	if filters == nil {
+		return params
+	}
+
+	if filters.ActorID != 0 {
+		params.ActorID = sql.NullInt64{Int64: filters.ActorID, Valid: true}
+	} else if filters.ActorName != "" {
+		params.ActorName = sql.NullString{String: filters.ActorName, Valid: true}
+	}
+
+	if filters.Name != "" {
+		params.Name = sql.NullString{String: filters.Name, Valid: true}
 	}
+
+	if filters.SceneNumber != 0 {
+		params.SceneNumber = sql.NullInt64{Int64: filters.SceneNumber, Valid: true}
+	}
+
+	if len(filters.ActorIDs) > 0 {
+		params.ActorIDs = sql.NullString{String: common.JSONIDs(filters.ActorIDs), Valid: true}
+	}
+
+	if len(filters.ExcludeActorIDs) > 0 {
+		params.ExcludeActorIDs = sql.NullString{String: common.JSONIDs(filters.ExcludeActorIDs), Valid: true}
+	}
+
+	if len(filters.SceneNumbers) > 0 {
+		params.SceneNumbers = sql.NullString{String: common.JSONIDs(filters.SceneNumbers), Valid: true}
+		params.MinScenes = 1
+		if filters.AllScenes {
+			params.MinScenes = int64(len(common.DistinctIDs(filters.SceneNumbers)))
+		}
+	}
+
+	if filters.SceneFrom != 0 {
+		params.SceneFrom = sql.NullInt64{Int64: filters.SceneFrom, Valid: true}
+	}
+
+	if filters.SceneTo != 0 {
+		params.SceneTo = sql.NullInt64{Int64: filters.SceneTo, Valid: true}
+	}
+
+	return params
+}
+
+// listParams is like filterParams, but also sorts the results by def unless
+// filters says otherwise. The parameters ask for every row.
+func listParams(filters *CharacterFilters, def common.OrderBy) (listCharactersParams, error) {
+	f := filterParams(filters)
+	params := listCharactersParams{
+		OrderBy:         string(def),
+		ActorID:         f.ActorID,
+		ActorName:       f.ActorName,
+		Name:            f.Name,
+		SceneNumber:     f.SceneNumber,
+		SceneFrom:       f.SceneFrom,
+		SceneTo:         f.SceneTo,
+		ActorIDs:        f.ActorIDs,
+		ExcludeActorIDs: f.ExcludeActorIDs,
+		SceneNumbers:    f.SceneNumbers,
+		MinScenes:       f.MinScenes,
+		Limit:           -1,
+	}
+	if filters == nil {
+		return params, nil
+	}
+
+	err := filters.OrderBy.Validate()
+	if err != nil {
+		return params, err
+	}
+	params.OrderBy = string(filters.OrderBy.Or(def))
+	params.Descending = filters.Descending
+
+	return params, nil
 }
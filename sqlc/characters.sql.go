@@ -1,24 +1,15 @@
 // Code generated by sqlc. DO NOT EDIT.
 // versions:
-//   sqlc v1.18.0
+//   sqlc v1.29.0
 // source: characters.sql
 
 package sqlc
 
 import (
 	"context"
+	"database/sql"
 )
 
-const deleteCharacter = `-- name: DeleteCharacter :exec
-DELETE FROM characters WHERE id = ?
-`
-
-// DeleteCharacter removes a character from the database.
-func (q *Queries) DeleteCharacter(ctx context.Context, id int64) error {
-	_, err := q.db.ExecContext(ctx, deleteCharacter, id)
-	return err
-}
-
 const getCharacter = `-- name: GetCharacter :one
 SELECT id, name, actor_id FROM characters WHERE id = ?
 `
@@ -31,38 +22,101 @@ func (q *Queries) GetCharacter(ctx context.Context, id int64) (Character, error)
 	return i, err
 }
 
-const insertCharacter = `-- name: insertCharacter :one
-INSERT INTO characters (actor_id, name) VALUES (?, ?) RETURNING id
+const countCharacters = `-- name: countCharacters :many
+WITH f AS (
+  SELECT CAST(?1 AS TEXT) AS actor_ids,
+    CAST(?2 AS TEXT) AS exclude_actor_ids,
+    CAST(?3 AS TEXT) AS scene_numbers,
+    CAST(?4 AS INTEGER) AS min_scenes,
+    CAST(?5 AS BOOLEAN) AS facets
+),
+m AS (
+  SELECT c.id, c.actor_id FROM characters c
+  WHERE (c.actor_id = ?6 OR ?6 IS NULL)
+    AND (EXISTS (
+      SELECT 1 FROM actors a
+      WHERE a.id = c.actor_id AND LOWER(a.name) LIKE '%' || LOWER(?7) || '%'
+    ) OR ?7 IS NULL)
+    AND (LOWER(c.name) LIKE '%' || LOWER(?8) || '%' OR ?8 IS NULL)
+    AND (EXISTS (
+      SELECT 1 FROM scene_characters sc
+      WHERE sc.character_id = c.id AND sc.scene_id = ?9
+    ) OR ?9 IS NULL)
+    AND (c.actor_id IN (SELECT j.value FROM f, json_each(f.actor_ids) j)
+      OR (SELECT actor_ids FROM f) IS NULL)
+    AND (c.actor_id NOT IN (SELECT j.value FROM f, json_each(f.exclude_actor_ids) j)
+      OR (SELECT exclude_actor_ids FROM f) IS NULL)
+    AND (c.id IN (
+      SELECT ssc.character_id FROM scene_characters ssc
+      WHERE ssc.scene_id IN (SELECT j.value FROM f, json_each(f.scene_numbers) j)
+      GROUP BY ssc.character_id
+      HAVING COUNT(*) >= (SELECT min_scenes FROM f)
+    ) OR (SELECT scene_numbers FROM f) IS NULL)
+    AND (c.id IN (
+      SELECT rsc.character_id FROM scene_characters rsc
+      WHERE (rsc.scene_id >= ?10 OR ?10 IS NULL)
+        AND (rsc.scene_id <= ?11 OR ?11 IS NULL)
+    ) OR (?10 IS NULL AND ?11 IS NULL))
+)
+SELECT 'total' AS facet, 0 AS id, COUNT(*) AS count FROM m
+UNION ALL
+SELECT 'actor', m.actor_id, COUNT(*) FROM m
+WHERE (SELECT facets FROM f)
+GROUP BY m.actor_id
+UNION ALL
+SELECT 'scene', fsc.scene_id, COUNT(*) FROM m
+JOIN scene_characters fsc ON fsc.character_id = m.id
+WHERE (SELECT facets FROM f)
+GROUP BY fsc.scene_id
+ORDER BY 1, 2
 `
 
-type insertCharacterParams struct {
-	ActorID int64
-	Name    string
+type countCharactersParams struct {
+	ActorIDs        sql.NullString
+	ExcludeActorIDs sql.NullString
+	SceneNumbers    sql.NullString
+	MinScenes       int64
+	Facets          bool
+	ActorID         sql.NullInt64
+	ActorName       sql.NullString
+	Name            sql.NullString
+	SceneNumber     sql.NullInt64
+	SceneFrom       sql.NullInt64
+	SceneTo         sql.NullInt64
 }
 
-// insertCharacter creates a new character record.
-func (q *Queries) insertCharacter(ctx context.Context, arg insertCharacterParams) (int64, error) {
-	row := q.db.QueryRowContext(ctx, insertCharacter, arg.ActorID, arg.Name)
-	var id int64
-	err := row.Scan(&id)
-	return id, err
+type countCharactersRow struct {
+	Facet string
+	ID    int64
+	Count int64
 }
 
-const listAllCharacters = `-- name: listAllCharacters :many
-SELECT id, name, actor_id FROM characters
-`
-
-// listAllCharacters returns all characters.
-func (q *Queries) listAllCharacters(ctx context.Context) ([]Character, error) {
-	rows, err := q.db.QueryContext(ctx, listAllCharacters)
+// countCharacters counts the characters listCharacters would return. It
+// returns a "total" row, and when facets is true, an "actor" row for each
+// actor and a "scene" row for each scene, with the actor or scene ID. The
+// filters are the same as listCharacters, applied once in m.
+func (q *Queries) countCharacters(ctx context.Context, arg countCharactersParams) ([]countCharactersRow, error) {
+	rows, err := q.db.QueryContext(ctx, countCharacters,
+		arg.ActorIDs,
+		arg.ExcludeActorIDs,
+		arg.SceneNumbers,
+		arg.MinScenes,
+		arg.Facets,
+		arg.ActorID,
+		arg.ActorName,
+		arg.Name,
+		arg.SceneNumber,
+		arg.SceneFrom,
+		arg.SceneTo,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Character
+	var items []countCharactersRow
 	for rows.Next() {
-		var i Character
-		if err := rows.Scan(&i.ID, &i.Name, &i.ActorID); err != nil {
+		var i countCharactersRow
+		if err := rows.Scan(&i.Facet, &i.ID, &i.Count); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -76,99 +130,152 @@ func (q *Queries) listAllCharacters(ctx context.Context) ([]Character, error) {
 	return items, nil
 }
 
-const listCharactersByActor = `-- name: listCharactersByActor :many
-SELECT id, name, actor_id FROM characters WHERE actor_id = ?
+const deleteCharacter = `-- name: deleteCharacter :execrows
+DELETE FROM characters WHERE id = ?
 `
 
-// listCharactersByActor returns all characters played a given actor.
-func (q *Queries) listCharactersByActor(ctx context.Context, actorID int64) ([]Character, error) {
-	rows, err := q.db.QueryContext(ctx, listCharactersByActor, actorID)
+// deleteCharacter removes a character from the database.
+func (q *Queries) deleteCharacter(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteCharacter, id)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
-	defer rows.Close()
-	var items []Character
-	for rows.Next() {
-		var i Character
-		if err := rows.Scan(&i.ID, &i.Name, &i.ActorID); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+	return result.RowsAffected()
 }
 
-const listCharactersByActorName = `-- name: listCharactersByActorName :many
-SELECT c.id, c.name, c.actor_id FROM characters c JOIN actors a ON c.actor_id = a.id WHERE LOWER(a.name) LIKE '%' || LOWER(?) || '%'
+const insertCharacter = `-- name: insertCharacter :one
+INSERT INTO characters (actor_id, name) VALUES (?, ?) RETURNING id
 `
 
-// listCharactersByActorName returns all characters played by an actor with a
-// name matching the given name.
-func (q *Queries) listCharactersByActorName(ctx context.Context, lower string) ([]Character, error) {
-	rows, err := q.db.QueryContext(ctx, listCharactersByActorName, lower)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []Character
-	for rows.Next() {
-		var i Character
-		if err := rows.Scan(&i.ID, &i.Name, &i.ActorID); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+type insertCharacterParams struct {
+	ActorID int64
+	Name    string
 }
 
-const listCharactersByName = `-- name: listCharactersByName :many
-SELECT id, name, actor_id FROM characters WHERE LOWER(name) LIKE '%' || LOWER(?) || '%'
-`
-
-// listCharactersByName returns all characters with a name matching the given
-// name.
-func (q *Queries) listCharactersByName(ctx context.Context, lower string) ([]Character, error) {
-	rows, err := q.db.QueryContext(ctx, listCharactersByName, lower)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []Character
-	for rows.Next() {
-		var i Character
-		if err := rows.Scan(&i.ID, &i.Name, &i.ActorID); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+// insertCharacter creates a new character record.
+func (q *Queries) insertCharacter(ctx context.Context, arg insertCharacterParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, insertCharacter, arg.ActorID, arg.Name)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
 }
 
-const listCharactersByScene = `-- name: listCharactersByScene :many
-SELECT c.id, c.name, c.actor_id FROM characters c JOIN scene_characters sc ON c.id = sc.character_id WHERE sc.scene_id = ?
+const listCharacters = `-- name: listCharacters :many
+WITH f AS (
+  SELECT CAST(?13 AS TEXT) AS actor_ids,
+    CAST(?14 AS TEXT) AS exclude_actor_ids,
+    CAST(?15 AS TEXT) AS scene_numbers,
+    CAST(?16 AS INTEGER) AS min_scenes
+)
+SELECT c.id, c.name, c.actor_id FROM characters c,
+  (SELECT CAST(?1 AS TEXT) AS order_by,
+    CAST(?2 AS BOOLEAN) AS descending) o
+WHERE (c.actor_id = ?3 OR ?3 IS NULL)
+  AND (EXISTS (
+    SELECT 1 FROM actors a
+    WHERE a.id = c.actor_id AND LOWER(a.name) LIKE '%' || LOWER(?4) || '%'
+  ) OR ?4 IS NULL)
+  AND (LOWER(c.name) LIKE '%' || LOWER(?5) || '%' OR ?5 IS NULL)
+  AND (EXISTS (
+    SELECT 1 FROM scene_characters sc
+    WHERE sc.character_id = c.id AND sc.scene_id = ?6
+  ) OR ?6 IS NULL)
+  AND (c.actor_id IN (SELECT j.value FROM f, json_each(f.actor_ids) j)
+    OR (SELECT actor_ids FROM f) IS NULL)
+  AND (c.actor_id NOT IN (SELECT j.value FROM f, json_each(f.exclude_actor_ids) j)
+    OR (SELECT exclude_actor_ids FROM f) IS NULL)
+  AND (c.id IN (
+    SELECT ssc.character_id FROM scene_characters ssc
+    WHERE ssc.scene_id IN (SELECT j.value FROM f, json_each(f.scene_numbers) j)
+    GROUP BY ssc.character_id
+    HAVING COUNT(*) >= (SELECT min_scenes FROM f)
+  ) OR (SELECT scene_numbers FROM f) IS NULL)
+  AND (c.id IN (
+    SELECT rsc.character_id FROM scene_characters rsc
+    WHERE (rsc.scene_id >= ?7 OR ?7 IS NULL)
+      AND (rsc.scene_id <= ?8 OR ?8 IS NULL)
+  ) OR (?7 IS NULL AND ?8 IS NULL))
+  AND (c.name > ?9
+    OR (c.name = ?9 AND c.id > ?10)
+    OR ?9 IS NULL)
+ORDER BY
+  CASE WHEN NOT o.descending THEN
+    CASE o.order_by
+      WHEN 'name' THEN c.name
+      WHEN 'actor_name' THEN (SELECT oa.name FROM actors oa WHERE oa.id = c.actor_id)
+      WHEN 'scene_count' THEN (SELECT COUNT(*) FROM scene_characters osc WHERE osc.character_id = c.id)
+      WHEN 'quote_count' THEN (SELECT COUNT(*) FROM quotes oq WHERE oq.character_id = c.id)
+    END
+  END,
+  CASE WHEN o.descending THEN
+    CASE o.order_by
+      WHEN 'name' THEN c.name
+      WHEN 'actor_name' THEN (SELECT oa.name FROM actors oa WHERE oa.id = c.actor_id)
+      WHEN 'scene_count' THEN (SELECT COUNT(*) FROM scene_characters osc WHERE osc.character_id = c.id)
+      WHEN 'quote_count' THEN (SELECT COUNT(*) FROM quotes oq WHERE oq.character_id = c.id)
+    END
+  END DESC,
+  CASE WHEN o.descending THEN c.id END DESC,
+  c.id
+LIMIT ?12 OFFSET ?11
 `
 
-// listCharactersByScene returns all characters in a given scene.
-func (q *Queries) listCharactersByScene(ctx context.Context, sceneID int64) ([]Character, error) {
-	rows, err := q.db.QueryContext(ctx, listCharactersByScene, sceneID)
+type listCharactersParams struct {
+	OrderBy         string
+	Descending      bool
+	ActorID         sql.NullInt64
+	ActorName       sql.NullString
+	Name            sql.NullString
+	SceneNumber     sql.NullInt64
+	SceneFrom       sql.NullInt64
+	SceneTo         sql.NullInt64
+	AfterName       sql.NullString
+	AfterID         sql.NullInt64
+	Offset          int64
+	Limit           int64
+	ActorIDs        sql.NullString
+	ExcludeActorIDs sql.NullString
+	SceneNumbers    sql.NullString
+	MinScenes       int64
+}
+
+// listCharacters returns the characters matching every filter that isn't
+// NULL, sorted by order_by and then ID. Each IS NULL check comes after the
+// comparison so sqlc infers the parameter's type from the comparison.
+//
+// actor_ids, exclude_actor_ids and scene_numbers are JSON arrays, so a list
+// of any length is one parameter. A character matches scene_numbers if it's
+// in at least min_scenes of them. sqlc doesn't find parameters in function
+// arguments either, so the lists are selected in f. Selecting from f in a
+// subquery, rather than joining it, means each list is only read once.
+//
+// The results start offset rows in, and after (after_name, after_id) if they
+// aren't NULL, which only makes sense in ascending order of name. A limit of
+// -1 returns every row.
+//
+// The sort column can't be a parameter, so each one has a branch of the CASE.
+// The whole CASE is NULL when it doesn't apply, which leaves the order to the
+// next one. order_by "id" has no branch, so it falls through to c.id. sqlc
+// doesn't find parameters in ORDER BY, so they're selected in the o subquery.
+func (q *Queries) listCharacters(ctx context.Context, arg listCharactersParams) ([]Character, error) {
+	rows, err := q.db.QueryContext(ctx, listCharacters,
+		arg.OrderBy,
+		arg.Descending,
+		arg.ActorID,
+		arg.ActorName,
+		arg.Name,
+		arg.SceneNumber,
+		arg.SceneFrom,
+		arg.SceneTo,
+		arg.AfterName,
+		arg.AfterID,
+		arg.Offset,
+		arg.Limit,
+		arg.ActorIDs,
+		arg.ExcludeActorIDs,
+		arg.SceneNumbers,
+		arg.MinScenes,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -190,7 +297,7 @@ func (q *Queries) listCharactersByScene(ctx context.Context, sceneID int64) ([]C
 	return items, nil
 }
 
-const updateCharacter = `-- name: updateCharacter :exec
+const updateCharacter = `-- name: updateCharacter :execrows
 UPDATE characters SET actor_id = ?, name = ? WHERE id = ?
 `
 
@@ -201,7 +308,10 @@ type updateCharacterParams struct {
 }
 
 // updateCharacter updates a character's information.
-func (q *Queries) updateCharacter(ctx context.Context, arg updateCharacterParams) error {
-	_, err := q.db.ExecContext(ctx, updateCharacter, arg.ActorID, arg.Name, arg.ID)
-	return err
+func (q *Queries) updateCharacter(ctx context.Context, arg updateCharacterParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateCharacter, arg.ActorID, arg.Name, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
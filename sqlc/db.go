@@ -1,6 +1,6 @@
 // Code generated by sqlc. DO NOT EDIT.
 // versions:
-//   sqlc v1.18.0
+//   sqlc v1.29.0
 
 package sqlc
 
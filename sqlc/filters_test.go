@@ -0,0 +1,89 @@
+package sqlc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pboyd/godbmodels/common"
+	"github.com/pboyd/godbmodels/vanilla"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestListCharactersMatrix runs every combination of filters through
+// ListCharacters and checks that the results match vanilla's.
+func TestListCharactersMatrix(t *testing.T) {
+	tx := common.TestTx(t)
+	q := New(tx)
+	cs := vanilla.NewCharacterStore(tx)
+
+	values := map[string]CharacterFilters{
+		// Michael Palin plays two knights in scene 6.
+		"Palin": {ActorID: 6, ActorName: "palin", Name: "knight", SceneNumber: 6},
+
+		// The actor ID and name disagree: Brother Maynard is Eric Idle,
+		// Brother Maynard's Brother is Michael Palin and is in scene 20.
+		"Conflicting": {ActorID: 3, ActorName: "palin", Name: "brother", SceneNumber: 20},
+	}
+
+	for set, v := range values {
+		for mask := 0; mask < 16; mask++ {
+			var f CharacterFilters
+			var fields []string
+			if mask&1 != 0 {
+				f.ActorID = v.ActorID
+				fields = append(fields, "ActorID")
+			}
+			if mask&2 != 0 {
+				f.ActorName = v.ActorName
+				fields = append(fields, "ActorName")
+			}
+			if mask&4 != 0 {
+				f.Name = v.Name
+				fields = append(fields, "Name")
+			}
+			if mask&8 != 0 {
+				f.SceneNumber = v.SceneNumber
+				fields = append(fields, "SceneNumber")
+			}
+
+			name := fmt.Sprintf("%s/%s", set, strings.Join(fields, "+"))
+			t.Run(name, func(t *testing.T) {
+				assert := assert.New(t)
+
+				expected, err := cs.List(context.Background(), &vanilla.CharacterFilters{
+					ActorID:     f.ActorID,
+					ActorName:   f.ActorName,
+					Name:        f.Name,
+					SceneNumber: f.SceneNumber,
+				})
+				if !assert.NoError(err) {
+					return
+				}
+
+				actual, err := q.ListCharacters(context.Background(), &f)
+				if !assert.NoError(err) {
+					return
+				}
+
+				expectedIDs := []int64{}
+				for _, c := range expected {
+					expectedIDs = append(expectedIDs, c.ID)
+				}
+				actualIDs := []int64{}
+				for _, c := range actual {
+					actualIDs = append(actualIDs, c.ID)
+				}
+				assert.ElementsMatch(expectedIDs, actualIDs)
+			})
+		}
+	}
+}
+
+func TestListCharactersNil(t *testing.T) {
+	characters, err := New(common.TestTx(t)).ListCharacters(context.Background(), nil)
+	if assert.NoError(t, err) {
+		assert.Len(t, characters, 81)
+	}
+}
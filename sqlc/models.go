@@ -1,11 +1,9 @@
 // Code generated by sqlc. DO NOT EDIT.
 // versions:
-//   sqlc v1.18.0
+//   sqlc v1.29.0
 
 package sqlc
 
-import ()
-
 type Actor struct {
 	ID   int64
 	Name string
@@ -24,6 +22,10 @@ type Quote struct {
 	Text        string
 }
 
+type QuotesFt struct {
+	Text string
+}
+
 type Scene struct {
 	ID   int64
 	Name string
@@ -0,0 +1,44 @@
+package sqlc
+
+import (
+	"os"
+	"regexp"
+	"sort"
+	"testing"
+
+	"github.com/pboyd/godbmodels/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestOrderByKeys checks that the ORDER BY CASE in listCharacters has a
+// branch for every common.OrderBy, and none that common doesn't know about.
+func TestOrderByKeys(t *testing.T) {
+	assert := assert.New(t)
+
+	query, err := os.ReadFile("queries/characters.sql")
+	if !assert.NoError(err) {
+		return
+	}
+
+	// The CASE is written twice, once for each direction.
+	counts := map[string]int{}
+	for _, m := range regexp.MustCompile(`WHEN '(\w+)' THEN`).FindAllSubmatch(query, -1) {
+		counts[string(m[1])]++
+	}
+
+	// "id" has no branch, it falls through to c.id.
+	keys := []string{string(common.OrderByID)}
+	for k, n := range counts {
+		assert.Equal(2, n, k)
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var expected []string
+	for _, o := range common.OrderBys {
+		expected = append(expected, string(o))
+	}
+	sort.Strings(expected)
+
+	assert.Equal(expected, keys)
+}
@@ -0,0 +1,23 @@
+-- name: GetActor :one
+-- GetActor loads an actor from the database by ID.
+SELECT * FROM actors WHERE id = ?;
+
+-- name: insertActor :one
+-- insertActor creates a new actor record.
+INSERT INTO actors (name) VALUES (?) RETURNING id;
+
+-- name: updateActor :execrows
+-- updateActor updates an actor's name.
+UPDATE actors SET name = ? WHERE id = ?;
+
+-- name: deleteActor :execrows
+-- deleteActor removes an actor from the database.
+DELETE FROM actors WHERE id = ?;
+
+-- name: listAllActors :many
+-- listAllActors returns all actors.
+SELECT * FROM actors ORDER BY id;
+
+-- name: listActorsByName :many
+-- listActorsByName returns all actors with a name matching the given name.
+SELECT * FROM actors WHERE LOWER(name) LIKE '%' || LOWER(?) || '%' ORDER BY id;
@@ -6,32 +6,139 @@ SELECT * FROM characters WHERE id = ?;
 -- insertCharacter creates a new character record.
 INSERT INTO characters (actor_id, name) VALUES (?, ?) RETURNING id;
 
--- name: updateCharacter :exec
+-- name: updateCharacter :execrows
 -- updateCharacter updates a character's information.
 UPDATE characters SET actor_id = ?, name = ? WHERE id = ?;
 
--- name: DeleteCharacter :exec
--- DeleteCharacter removes a character from the database.
+-- name: deleteCharacter :execrows
+-- deleteCharacter removes a character from the database.
 DELETE FROM characters WHERE id = ?;
 
--- name: listAllCharacters :many
--- listAllCharacters returns all characters.
-SELECT * FROM characters;
+-- name: listCharacters :many
+-- listCharacters returns the characters matching every filter that isn't
+-- NULL, sorted by order_by and then ID. Each IS NULL check comes after the
+-- comparison so sqlc infers the parameter's type from the comparison.
+--
+-- actor_ids, exclude_actor_ids and scene_numbers are JSON arrays, so a list
+-- of any length is one parameter. A character matches scene_numbers if it's
+-- in at least min_scenes of them. sqlc doesn't find parameters in function
+-- arguments either, so the lists are selected in f. Selecting from f in a
+-- subquery, rather than joining it, means each list is only read once.
+--
+-- The results start offset rows in, and after (after_name, after_id) if they
+-- aren't NULL, which only makes sense in ascending order of name. A limit of
+-- -1 returns every row.
+--
+-- The sort column can't be a parameter, so each one has a branch of the CASE.
+-- The whole CASE is NULL when it doesn't apply, which leaves the order to the
+-- next one. order_by "id" has no branch, so it falls through to c.id. sqlc
+-- doesn't find parameters in ORDER BY, so they're selected in the o subquery.
+WITH f AS (
+  SELECT CAST(sqlc.narg(actor_ids) AS TEXT) AS actor_ids,
+    CAST(sqlc.narg(exclude_actor_ids) AS TEXT) AS exclude_actor_ids,
+    CAST(sqlc.narg(scene_numbers) AS TEXT) AS scene_numbers,
+    CAST(sqlc.arg(min_scenes) AS INTEGER) AS min_scenes
+)
+SELECT c.* FROM characters c,
+  (SELECT CAST(sqlc.arg(order_by) AS TEXT) AS order_by,
+    CAST(sqlc.arg(descending) AS BOOLEAN) AS descending) o
+WHERE (c.actor_id = sqlc.narg(actor_id) OR sqlc.narg(actor_id) IS NULL)
+  AND (EXISTS (
+    SELECT 1 FROM actors a
+    WHERE a.id = c.actor_id AND LOWER(a.name) LIKE '%' || LOWER(sqlc.narg(actor_name)) || '%'
+  ) OR sqlc.narg(actor_name) IS NULL)
+  AND (LOWER(c.name) LIKE '%' || LOWER(sqlc.narg(name)) || '%' OR sqlc.narg(name) IS NULL)
+  AND (EXISTS (
+    SELECT 1 FROM scene_characters sc
+    WHERE sc.character_id = c.id AND sc.scene_id = sqlc.narg(scene_number)
+  ) OR sqlc.narg(scene_number) IS NULL)
+  AND (c.actor_id IN (SELECT j.value FROM f, json_each(f.actor_ids) j)
+    OR (SELECT actor_ids FROM f) IS NULL)
+  AND (c.actor_id NOT IN (SELECT j.value FROM f, json_each(f.exclude_actor_ids) j)
+    OR (SELECT exclude_actor_ids FROM f) IS NULL)
+  AND (c.id IN (
+    SELECT ssc.character_id FROM scene_characters ssc
+    WHERE ssc.scene_id IN (SELECT j.value FROM f, json_each(f.scene_numbers) j)
+    GROUP BY ssc.character_id
+    HAVING COUNT(*) >= (SELECT min_scenes FROM f)
+  ) OR (SELECT scene_numbers FROM f) IS NULL)
+  AND (c.id IN (
+    SELECT rsc.character_id FROM scene_characters rsc
+    WHERE (rsc.scene_id >= sqlc.narg(scene_from) OR sqlc.narg(scene_from) IS NULL)
+      AND (rsc.scene_id <= sqlc.narg(scene_to) OR sqlc.narg(scene_to) IS NULL)
+  ) OR (sqlc.narg(scene_from) IS NULL AND sqlc.narg(scene_to) IS NULL))
+  AND (c.name > sqlc.narg(after_name)
+    OR (c.name = sqlc.narg(after_name) AND c.id > sqlc.narg(after_id))
+    OR sqlc.narg(after_name) IS NULL)
+ORDER BY
+  CASE WHEN NOT o.descending THEN
+    CASE o.order_by
+      WHEN 'name' THEN c.name
+      WHEN 'actor_name' THEN (SELECT oa.name FROM actors oa WHERE oa.id = c.actor_id)
+      WHEN 'scene_count' THEN (SELECT COUNT(*) FROM scene_characters osc WHERE osc.character_id = c.id)
+      WHEN 'quote_count' THEN (SELECT COUNT(*) FROM quotes oq WHERE oq.character_id = c.id)
+    END
+  END,
+  CASE WHEN o.descending THEN
+    CASE o.order_by
+      WHEN 'name' THEN c.name
+      WHEN 'actor_name' THEN (SELECT oa.name FROM actors oa WHERE oa.id = c.actor_id)
+      WHEN 'scene_count' THEN (SELECT COUNT(*) FROM scene_characters osc WHERE osc.character_id = c.id)
+      WHEN 'quote_count' THEN (SELECT COUNT(*) FROM quotes oq WHERE oq.character_id = c.id)
+    END
+  END DESC,
+  CASE WHEN o.descending THEN c.id END DESC,
+  c.id
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
 
--- name: listCharactersByActor :many
--- listCharactersByActor returns all characters played a given actor.
-SELECT * FROM characters WHERE actor_id = ?;
-
--- name: listCharactersByActorName :many
--- listCharactersByActorName returns all characters played by an actor with a
--- name matching the given name.
-SELECT c.* FROM characters c JOIN actors a ON c.actor_id = a.id WHERE LOWER(a.name) LIKE '%' || LOWER(?) || '%';
-
--- name: listCharactersByName :many
--- listCharactersByName returns all characters with a name matching the given
--- name.
-SELECT * FROM characters WHERE LOWER(name) LIKE '%' || LOWER(?) || '%';
-
--- name: listCharactersByScene :many
--- listCharactersByScene returns all characters in a given scene.
-SELECT c.* FROM characters c JOIN scene_characters sc ON c.id = sc.character_id WHERE sc.scene_id = ?;
+-- name: countCharacters :many
+-- countCharacters counts the characters listCharacters would return. It
+-- returns a "total" row, and when facets is true, an "actor" row for each
+-- actor and a "scene" row for each scene, with the actor or scene ID. The
+-- filters are the same as listCharacters, applied once in m.
+WITH f AS (
+  SELECT CAST(sqlc.narg(actor_ids) AS TEXT) AS actor_ids,
+    CAST(sqlc.narg(exclude_actor_ids) AS TEXT) AS exclude_actor_ids,
+    CAST(sqlc.narg(scene_numbers) AS TEXT) AS scene_numbers,
+    CAST(sqlc.arg(min_scenes) AS INTEGER) AS min_scenes,
+    CAST(sqlc.arg(facets) AS BOOLEAN) AS facets
+),
+m AS (
+  SELECT c.id, c.actor_id FROM characters c
+  WHERE (c.actor_id = sqlc.narg(actor_id) OR sqlc.narg(actor_id) IS NULL)
+    AND (EXISTS (
+      SELECT 1 FROM actors a
+      WHERE a.id = c.actor_id AND LOWER(a.name) LIKE '%' || LOWER(sqlc.narg(actor_name)) || '%'
+    ) OR sqlc.narg(actor_name) IS NULL)
+    AND (LOWER(c.name) LIKE '%' || LOWER(sqlc.narg(name)) || '%' OR sqlc.narg(name) IS NULL)
+    AND (EXISTS (
+      SELECT 1 FROM scene_characters sc
+      WHERE sc.character_id = c.id AND sc.scene_id = sqlc.narg(scene_number)
+    ) OR sqlc.narg(scene_number) IS NULL)
+    AND (c.actor_id IN (SELECT j.value FROM f, json_each(f.actor_ids) j)
+      OR (SELECT actor_ids FROM f) IS NULL)
+    AND (c.actor_id NOT IN (SELECT j.value FROM f, json_each(f.exclude_actor_ids) j)
+      OR (SELECT exclude_actor_ids FROM f) IS NULL)
+    AND (c.id IN (
+      SELECT ssc.character_id FROM scene_characters ssc
+      WHERE ssc.scene_id IN (SELECT j.value FROM f, json_each(f.scene_numbers) j)
+      GROUP BY ssc.character_id
+      HAVING COUNT(*) >= (SELECT min_scenes FROM f)
+    ) OR (SELECT scene_numbers FROM f) IS NULL)
+    AND (c.id IN (
+      SELECT rsc.character_id FROM scene_characters rsc
+      WHERE (rsc.scene_id >= sqlc.narg(scene_from) OR sqlc.narg(scene_from) IS NULL)
+        AND (rsc.scene_id <= sqlc.narg(scene_to) OR sqlc.narg(scene_to) IS NULL)
+    ) OR (sqlc.narg(scene_from) IS NULL AND sqlc.narg(scene_to) IS NULL))
+)
+SELECT 'total' AS facet, 0 AS id, COUNT(*) AS count FROM m
+UNION ALL
+SELECT 'actor', m.actor_id, COUNT(*) FROM m
+WHERE (SELECT facets FROM f)
+GROUP BY m.actor_id
+UNION ALL
+SELECT 'scene', fsc.scene_id, COUNT(*) FROM m
+JOIN scene_characters fsc ON fsc.character_id = m.id
+WHERE (SELECT facets FROM f)
+GROUP BY fsc.scene_id
+ORDER BY 1, 2;
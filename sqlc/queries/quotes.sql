@@ -0,0 +1,32 @@
+-- name: GetQuote :one
+-- GetQuote loads a quote from the database by ID.
+SELECT * FROM quotes WHERE id = ?;
+
+-- name: insertQuote :one
+-- insertQuote creates a new quote record.
+INSERT INTO quotes (character_id, scene_id, text) VALUES (?, ?, ?) RETURNING id;
+
+-- name: updateQuote :execrows
+-- updateQuote updates a quote's information.
+UPDATE quotes SET character_id = ?, scene_id = ?, text = ? WHERE id = ?;
+
+-- name: deleteQuote :execrows
+-- deleteQuote removes a quote from the database.
+DELETE FROM quotes WHERE id = ?;
+
+-- name: listQuotes :many
+-- listQuotes returns the quotes matching every filter that isn't NULL.
+SELECT * FROM quotes
+WHERE (character_id = sqlc.narg(character_id) OR sqlc.narg(character_id) IS NULL)
+  AND (scene_id = sqlc.narg(scene_number) OR sqlc.narg(scene_number) IS NULL)
+  AND (LOWER(text) LIKE '%' || LOWER(sqlc.narg(text)) || '%' OR sqlc.narg(text) IS NULL)
+ORDER BY id;
+
+-- name: ListSceneQuotes :many
+-- ListSceneQuotes returns the quotes from a scene along with the name of the
+-- character who said each one.
+SELECT q.id, q.character_id, c.name AS character_name, q.text
+FROM quotes q
+JOIN characters c ON c.id = q.character_id
+WHERE q.scene_id = ?
+ORDER BY q.id;
@@ -0,0 +1,39 @@
+-- name: GetScene :one
+-- GetScene loads a scene from the database by scene number.
+SELECT * FROM scenes WHERE id = ?;
+
+-- name: insertScene :one
+-- insertScene creates a new scene record with the next scene number.
+INSERT INTO scenes (name) VALUES (?) RETURNING id;
+
+-- name: updateScene :execrows
+-- updateScene updates a scene's name.
+UPDATE scenes SET name = ? WHERE id = ?;
+
+-- name: deleteScene :execrows
+-- deleteScene removes a scene from the database.
+DELETE FROM scenes WHERE id = ?;
+
+-- name: ListScenes :many
+-- ListScenes returns all scenes, in order.
+SELECT * FROM scenes ORDER BY id;
+
+-- name: ListSceneRoster :many
+-- ListSceneRoster returns the characters in a scene along with the actors
+-- who play them.
+SELECT c.id, c.name, c.actor_id, a.name AS actor_name
+FROM scene_characters sc
+JOIN characters c ON c.id = sc.character_id
+JOIN actors a ON a.id = c.actor_id
+WHERE sc.scene_id = ?
+ORDER BY c.id;
+
+-- name: AddSceneCharacter :exec
+-- AddSceneCharacter adds a character to a scene. Nothing happens if the
+-- character is already in the scene.
+INSERT INTO scene_characters (scene_id, character_id) VALUES (?, ?)
+ON CONFLICT DO NOTHING;
+
+-- name: RemoveSceneCharacter :exec
+-- RemoveSceneCharacter removes a character from a scene.
+DELETE FROM scene_characters WHERE scene_id = ? AND character_id = ?;
@@ -0,0 +1,91 @@
+package sqlc
+
+import (
+	"context"
+	"database/sql"
+)
+
+// StoreQuote saves a quote to the database. If the quote has an ID, it will
+// be updated. Otherwise, it will be inserted and the ID will be set.
+//
+// If the quote has an ID and it does not exist in the database, StoreQuote
+// returns ErrNotFound.
+func (q *Queries) StoreQuote(ctx context.Context, qt *Quote) error {
+	if qt.ID == 0 {
+		id, err := q.insertQuote(ctx, insertQuoteParams{
+			CharacterID: qt.CharacterID,
+			SceneID:     qt.SceneID,
+			Text:        qt.Text,
+		})
+		if err != nil {
+			return err
+		}
+
+		qt.ID = id
+		return nil
+	}
+
+	rows, err := q.updateQuote(ctx, updateQuoteParams{
+		ID:          qt.ID,
+		CharacterID: qt.CharacterID,
+		SceneID:     qt.SceneID,
+		Text:        qt.Text,
+	})
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// QuoteFilters are used to filter the results of a ListQuotes query.
+type QuoteFilters struct {
+	// CharacterID matches on the ID of the character who said the quote.
+	CharacterID int64
+
+	// SceneNumber matches on the scene the quote is from.
+	SceneNumber int64
+
+	// Text does a case-insensitive partial match on the quote.
+	Text string
+}
+
+// ListQuotes searches for quotes in the database, in order of ID.
+//
+// If filters is nil, all quotes are returned. Otherwise, the results are
+// filtered by the criteria in filters.
+func (q *Queries) ListQuotes(ctx context.Context, filters *QuoteFilters) ([]Quote, error) {
+	var params listQuotesParams
+	if filters != nil {
+		if filters.CharacterID != 0 {
+			params.CharacterID = sql.NullInt64{Int64: filters.CharacterID, Valid: true}
+		}
+		if filters.SceneNumber != 0 {
+			params.SceneNumber = sql.NullInt64{Int64: filters.SceneNumber, Valid: true}
+		}
+		if filters.Text != "" {
+			params.Text = sql.NullString{String: filters.Text, Valid: true}
+		}
+	}
+
+	return q.listQuotes(ctx, params)
+}
+
+// DeleteQuote removes a quote from the database.
+//
+// If the quote does not exist in the database, DeleteQuote returns
+// ErrNotFound.
+func (q *Queries) DeleteQuote(ctx context.Context, id int64) error {
+	rows, err := q.deleteQuote(ctx, id)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,172 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: quotes.sql
+
+package sqlc
+
+import (
+	"context"
+	"database/sql"
+)
+
+const getQuote = `-- name: GetQuote :one
+SELECT id, character_id, scene_id, text FROM quotes WHERE id = ?
+`
+
+// GetQuote loads a quote from the database by ID.
+func (q *Queries) GetQuote(ctx context.Context, id int64) (Quote, error) {
+	row := q.db.QueryRowContext(ctx, getQuote, id)
+	var i Quote
+	err := row.Scan(
+		&i.ID,
+		&i.CharacterID,
+		&i.SceneID,
+		&i.Text,
+	)
+	return i, err
+}
+
+const listSceneQuotes = `-- name: ListSceneQuotes :many
+SELECT q.id, q.character_id, c.name AS character_name, q.text
+FROM quotes q
+JOIN characters c ON c.id = q.character_id
+WHERE q.scene_id = ?
+ORDER BY q.id
+`
+
+type ListSceneQuotesRow struct {
+	ID            int64
+	CharacterID   int64
+	CharacterName string
+	Text          string
+}
+
+// ListSceneQuotes returns the quotes from a scene along with the name of the
+// character who said each one.
+func (q *Queries) ListSceneQuotes(ctx context.Context, sceneID int64) ([]ListSceneQuotesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listSceneQuotes, sceneID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListSceneQuotesRow
+	for rows.Next() {
+		var i ListSceneQuotesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CharacterID,
+			&i.CharacterName,
+			&i.Text,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteQuote = `-- name: deleteQuote :execrows
+DELETE FROM quotes WHERE id = ?
+`
+
+// deleteQuote removes a quote from the database.
+func (q *Queries) deleteQuote(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteQuote, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const insertQuote = `-- name: insertQuote :one
+INSERT INTO quotes (character_id, scene_id, text) VALUES (?, ?, ?) RETURNING id
+`
+
+type insertQuoteParams struct {
+	CharacterID int64
+	SceneID     int64
+	Text        string
+}
+
+// insertQuote creates a new quote record.
+func (q *Queries) insertQuote(ctx context.Context, arg insertQuoteParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, insertQuote, arg.CharacterID, arg.SceneID, arg.Text)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const listQuotes = `-- name: listQuotes :many
+SELECT id, character_id, scene_id, text FROM quotes
+WHERE (character_id = ?1 OR ?1 IS NULL)
+  AND (scene_id = ?2 OR ?2 IS NULL)
+  AND (LOWER(text) LIKE '%' || LOWER(?3) || '%' OR ?3 IS NULL)
+ORDER BY id
+`
+
+type listQuotesParams struct {
+	CharacterID sql.NullInt64
+	SceneNumber sql.NullInt64
+	Text        sql.NullString
+}
+
+// listQuotes returns the quotes matching every filter that isn't NULL.
+func (q *Queries) listQuotes(ctx context.Context, arg listQuotesParams) ([]Quote, error) {
+	rows, err := q.db.QueryContext(ctx, listQuotes, arg.CharacterID, arg.SceneNumber, arg.Text)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Quote
+	for rows.Next() {
+		var i Quote
+		if err := rows.Scan(
+			&i.ID,
+			&i.CharacterID,
+			&i.SceneID,
+			&i.Text,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateQuote = `-- name: updateQuote :execrows
+UPDATE quotes SET character_id = ?, scene_id = ?, text = ? WHERE id = ?
+`
+
+type updateQuoteParams struct {
+	CharacterID int64
+	SceneID     int64
+	Text        string
+	ID          int64
+}
+
+// updateQuote updates a quote's information.
+func (q *Queries) updateQuote(ctx context.Context, arg updateQuoteParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateQuote,
+		arg.CharacterID,
+		arg.SceneID,
+		arg.Text,
+		arg.ID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,130 @@
+package sqlc
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/pboyd/godbmodels/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuotes(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	q := New(common.TestTx(t))
+	qt := Quote{CharacterID: 34, SceneID: 3, Text: "Help! Help! I'm being repressed!"}
+
+	// Create
+	err := q.StoreQuote(ctx, &qt)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.NotZero(qt.ID)
+
+	// Read
+	qt2, err := q.GetQuote(ctx, qt.ID)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Equal(qt, qt2)
+
+	// Update
+	qt.CharacterID = 1
+	qt.Text = "Bloody peasant!"
+	err = q.StoreQuote(ctx, &qt)
+	if !assert.NoError(err) {
+		return
+	}
+	qt2, err = q.GetQuote(ctx, qt.ID)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Equal(qt, qt2)
+
+	// Delete
+	err = q.DeleteQuote(ctx, qt.ID)
+	if !assert.NoError(err) {
+		return
+	}
+	_, err = q.GetQuote(ctx, qt.ID)
+	assert.ErrorIs(err, sql.ErrNoRows)
+
+	// Update missing
+	err = q.StoreQuote(ctx, &qt)
+	assert.ErrorIs(err, ErrNotFound)
+
+	// Delete missing
+	err = q.DeleteQuote(ctx, qt.ID)
+	assert.ErrorIs(err, ErrNotFound)
+}
+
+func TestListQuotes(t *testing.T) {
+	cases := map[string]struct {
+		filters  *QuoteFilters
+		expected []int64
+	}{
+		"Nil": {
+			expected: []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12},
+		},
+		"Character": {
+			filters:  &QuoteFilters{CharacterID: 1},
+			expected: []int64{1, 5, 9},
+		},
+		"Scene": {
+			filters:  &QuoteFilters{SceneNumber: 6},
+			expected: []int64{5, 11, 12},
+		},
+		"Text": {
+			filters:  &QuoteFilters{Text: "MODEL"},
+			expected: []int64{12},
+		},
+		"CharacterAndScene": {
+			filters:  &QuoteFilters{CharacterID: 1, SceneNumber: 6},
+			expected: []int64{5},
+		},
+		"SceneAndText": {
+			filters:  &QuoteFilters{SceneNumber: 6, Text: "the"},
+			expected: []int64{11},
+		},
+		"NoMatch": {
+			filters: &QuoteFilters{CharacterID: 1, Text: "model"},
+		},
+	}
+
+	q := New(common.TestTx(t))
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			quotes, err := q.ListQuotes(context.Background(), tc.filters)
+			if !assert.NoError(err) {
+				return
+			}
+
+			var ids []int64
+			for _, qt := range quotes {
+				ids = append(ids, qt.ID)
+			}
+			assert.Equal(tc.expected, ids)
+		})
+	}
+}
+
+func TestListSceneQuotes(t *testing.T) {
+	assert := assert.New(t)
+	q := New(common.TestTx(t))
+
+	quotes, err := q.ListSceneQuotes(context.Background(), 6)
+	if !assert.NoError(err) || !assert.Len(quotes, 3) {
+		return
+	}
+
+	names := make([]string, len(quotes))
+	for i, qt := range quotes {
+		names[i] = qt.CharacterName
+	}
+	assert.Equal([]string{"King Arthur", "Sir Bedevere", "Patsy"}, names)
+	assert.Equal("It's only a model.", quotes[2].Text)
+}
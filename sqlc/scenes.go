@@ -0,0 +1,53 @@
+package sqlc
+
+import (
+	"context"
+)
+
+// StoreScene saves a scene to the database. If the scene has an ID, it will
+// be updated. Otherwise, it will be inserted with the next scene number and
+// the ID will be set.
+//
+// If the scene has an ID and it does not exist in the database, StoreScene
+// returns ErrNotFound.
+func (q *Queries) StoreScene(ctx context.Context, s *Scene) error {
+	if s.ID == 0 {
+		id, err := q.insertScene(ctx, s.Name)
+		if err != nil {
+			return err
+		}
+
+		s.ID = id
+		return nil
+	}
+
+	rows, err := q.updateScene(ctx, updateSceneParams{
+		ID:   s.ID,
+		Name: s.Name,
+	})
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteScene removes a scene from the database. It fails if the scene still
+// has characters or quotes.
+//
+// If the scene does not exist in the database, DeleteScene returns
+// ErrNotFound.
+func (q *Queries) DeleteScene(ctx context.Context, id int64) error {
+	rows, err := q.deleteScene(ctx, id)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,171 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: scenes.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const addSceneCharacter = `-- name: AddSceneCharacter :exec
+INSERT INTO scene_characters (scene_id, character_id) VALUES (?, ?)
+ON CONFLICT DO NOTHING
+`
+
+type AddSceneCharacterParams struct {
+	SceneID     int64
+	CharacterID int64
+}
+
+// AddSceneCharacter adds a character to a scene. Nothing happens if the
+// character is already in the scene.
+func (q *Queries) AddSceneCharacter(ctx context.Context, arg AddSceneCharacterParams) error {
+	_, err := q.db.ExecContext(ctx, addSceneCharacter, arg.SceneID, arg.CharacterID)
+	return err
+}
+
+const getScene = `-- name: GetScene :one
+SELECT id, name FROM scenes WHERE id = ?
+`
+
+// GetScene loads a scene from the database by scene number.
+func (q *Queries) GetScene(ctx context.Context, id int64) (Scene, error) {
+	row := q.db.QueryRowContext(ctx, getScene, id)
+	var i Scene
+	err := row.Scan(&i.ID, &i.Name)
+	return i, err
+}
+
+const listSceneRoster = `-- name: ListSceneRoster :many
+SELECT c.id, c.name, c.actor_id, a.name AS actor_name
+FROM scene_characters sc
+JOIN characters c ON c.id = sc.character_id
+JOIN actors a ON a.id = c.actor_id
+WHERE sc.scene_id = ?
+ORDER BY c.id
+`
+
+type ListSceneRosterRow struct {
+	ID        int64
+	Name      string
+	ActorID   int64
+	ActorName string
+}
+
+// ListSceneRoster returns the characters in a scene along with the actors
+// who play them.
+func (q *Queries) ListSceneRoster(ctx context.Context, sceneID int64) ([]ListSceneRosterRow, error) {
+	rows, err := q.db.QueryContext(ctx, listSceneRoster, sceneID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListSceneRosterRow
+	for rows.Next() {
+		var i ListSceneRosterRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.ActorID,
+			&i.ActorName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listScenes = `-- name: ListScenes :many
+SELECT id, name FROM scenes ORDER BY id
+`
+
+// ListScenes returns all scenes, in order.
+func (q *Queries) ListScenes(ctx context.Context) ([]Scene, error) {
+	rows, err := q.db.QueryContext(ctx, listScenes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Scene
+	for rows.Next() {
+		var i Scene
+		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const removeSceneCharacter = `-- name: RemoveSceneCharacter :exec
+DELETE FROM scene_characters WHERE scene_id = ? AND character_id = ?
+`
+
+type RemoveSceneCharacterParams struct {
+	SceneID     int64
+	CharacterID int64
+}
+
+// RemoveSceneCharacter removes a character from a scene.
+func (q *Queries) RemoveSceneCharacter(ctx context.Context, arg RemoveSceneCharacterParams) error {
+	_, err := q.db.ExecContext(ctx, removeSceneCharacter, arg.SceneID, arg.CharacterID)
+	return err
+}
+
+const deleteScene = `-- name: deleteScene :execrows
+DELETE FROM scenes WHERE id = ?
+`
+
+// deleteScene removes a scene from the database.
+func (q *Queries) deleteScene(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteScene, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const insertScene = `-- name: insertScene :one
+INSERT INTO scenes (name) VALUES (?) RETURNING id
+`
+
+// insertScene creates a new scene record with the next scene number.
+func (q *Queries) insertScene(ctx context.Context, name string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, insertScene, name)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const updateScene = `-- name: updateScene :execrows
+UPDATE scenes SET name = ? WHERE id = ?
+`
+
+type updateSceneParams struct {
+	Name string
+	ID   int64
+}
+
+// updateScene updates a scene's name.
+func (q *Queries) updateScene(ctx context.Context, arg updateSceneParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateScene, arg.Name, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
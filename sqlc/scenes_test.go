@@ -0,0 +1,99 @@
+package sqlc
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/pboyd/godbmodels/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScenes(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	q := New(common.TestTx(t))
+	s := Scene{Name: "The Black Beast of Aaaaargh"}
+
+	// Create
+	err := q.StoreScene(ctx, &s)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Equal(int64(24), s.ID)
+
+	// Read
+	s2, err := q.GetScene(ctx, s.ID)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Equal(s, s2)
+
+	// Update
+	s.Name = "The Cartoon Peril"
+	err = q.StoreScene(ctx, &s)
+	if !assert.NoError(err) {
+		return
+	}
+	scenes, err := q.ListScenes(ctx)
+	if assert.NoError(err) && assert.Len(scenes, 24) {
+		assert.Equal(s, scenes[23])
+	}
+
+	// Delete
+	err = q.DeleteScene(ctx, s.ID)
+	if !assert.NoError(err) {
+		return
+	}
+	_, err = q.GetScene(ctx, s.ID)
+	assert.ErrorIs(err, sql.ErrNoRows)
+
+	// Update missing
+	err = q.StoreScene(ctx, &s)
+	assert.ErrorIs(err, ErrNotFound)
+
+	// Delete missing
+	err = q.DeleteScene(ctx, s.ID)
+	assert.ErrorIs(err, ErrNotFound)
+}
+
+func TestSceneRoster(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	q := New(common.TestTx(t))
+
+	roster, err := q.ListSceneRoster(ctx, 3)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Equal([]ListSceneRosterRow{
+		{ID: 1, Name: "King Arthur", ActorID: 1, ActorName: "Graham Chapman"},
+		{ID: 20, Name: "Patsy", ActorID: 4, ActorName: "Terry Gilliam"},
+		{ID: 27, Name: "Dennis's Mother", ActorID: 5, ActorName: "Terry Jones"},
+		{ID: 34, Name: "Dennis", ActorID: 6, ActorName: "Michael Palin"},
+	}, roster)
+
+	// Adding twice is a no-op.
+	for i := 0; i < 2; i++ {
+		err = q.AddSceneCharacter(ctx, AddSceneCharacterParams{SceneID: 3, CharacterID: 2})
+		if !assert.NoError(err) {
+			return
+		}
+	}
+	err = q.RemoveSceneCharacter(ctx, RemoveSceneCharacterParams{SceneID: 3, CharacterID: 20})
+	if !assert.NoError(err) {
+		return
+	}
+
+	roster, err = q.ListSceneRoster(ctx, 3)
+	if !assert.NoError(err) {
+		return
+	}
+	var ids []int64
+	for _, r := range roster {
+		ids = append(ids, r.ID)
+	}
+	assert.Equal([]int64{1, 2, 27, 34}, ids)
+}
@@ -0,0 +1,37 @@
+package sqlc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pboyd/godbmodels/common/introspect"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSchema checks that the generated models are up to date with the
+// migrations.
+func TestSchema(t *testing.T) {
+	expected, err := introspect.Expected(context.Background())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	models := map[string]interface{}{
+		"actors":           Actor{},
+		"characters":       Character{},
+		"quotes":           Quote{},
+		"scene_characters": SceneCharacter{},
+		"scenes":           Scene{},
+	}
+
+	for name, model := range models {
+		table := expected.Table(name)
+		if !assert.NotNil(t, table, "no table %s", name) {
+			continue
+		}
+
+		for _, d := range table.CheckFields(introspect.StructFields(model)) {
+			t.Errorf("%T: %s", model, d)
+		}
+	}
+}
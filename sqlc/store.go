@@ -0,0 +1,94 @@
+package sqlc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/pboyd/godbmodels/common"
+)
+
+// CharacterStore wraps the generated character queries with the same
+// contract as the other packages' stores: Get returns nil when the character
+// doesn't exist, and updates and deletes of missing characters return
+// ErrNotFound.
+type CharacterStore struct {
+	q *Queries
+}
+
+// NewCharacterStore creates a new CharacterStore. db can be a *sql.DB or a
+// *sql.Tx.
+func NewCharacterStore(db DBTX) *CharacterStore {
+	return &CharacterStore{q: New(db)}
+}
+
+// Get loads a character from the database by ID.
+//
+// If no character is found, Get returns a nil Character and no error.
+func (cs *CharacterStore) Get(ctx context.Context, id int64) (*Character, error) {
+	c, err := cs.q.GetCharacter(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// Store saves a character to the database. If the character has an ID, it
+// will be updated. Otherwise, it will be inserted and the ID will be set.
+//
+// If the character has an ID and it does not exist in the database, Store
+// returns ErrNotFound.
+func (cs *CharacterStore) Store(ctx context.Context, c *Character) error {
+	return cs.q.StoreCharacter(ctx, c)
+}
+
+// Delete removes a character from the database.
+//
+// If the character does not exist in the database, Delete returns
+// ErrNotFound.
+func (cs *CharacterStore) Delete(ctx context.Context, id int64) error {
+	return cs.q.DeleteCharacter(ctx, id)
+}
+
+// List searches for characters in the database.
+//
+// If filters is nil, all characters are returned, in order of ID. Otherwise,
+// the results are filtered and sorted by the criteria in filters.
+func (cs *CharacterStore) List(ctx context.Context, filters *CharacterFilters) ([]*Character, error) {
+	characters, err := cs.q.ListCharacters(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*Character, len(characters))
+	for i := range characters {
+		list[i] = &characters[i]
+	}
+
+	return list, nil
+}
+
+// ListPage is like List, but returns one page of the results. The default
+// order is by name.
+//
+// If page is invalid, the error wraps common.ErrInvalidPage or
+// common.ErrInvalidCursor.
+func (cs *CharacterStore) ListPage(ctx context.Context, filters *CharacterFilters, page common.Page) (*CharacterPage, error) {
+	return cs.q.ListCharactersPage(ctx, filters, page)
+}
+
+// Count returns the number of characters that List would return for filters.
+// filters.OrderBy and Descending are ignored.
+func (cs *CharacterStore) Count(ctx context.Context, filters *CharacterFilters) (int, error) {
+	return cs.q.CountCharacters(ctx, filters)
+}
+
+// ListWithFacets is like List, but also counts the matching characters by
+// actor and by scene.
+func (cs *CharacterStore) ListWithFacets(ctx context.Context, filters *CharacterFilters) (*CharacterList, error) {
+	return cs.q.ListCharactersWithFacets(ctx, filters)
+}
@@ -0,0 +1,57 @@
+package sqlc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pboyd/godbmodels/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCharacterStore(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	cs := NewCharacterStore(common.TestTx(t))
+	c := &Character{
+		Name:    "Sir Not-Appearing-in-this-Film",
+		ActorID: 1,
+	}
+
+	// Create
+	err := cs.Store(ctx, c)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.NotZero(c.ID)
+
+	// Read
+	c2, err := cs.Get(ctx, c.ID)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Equal(c, c2)
+
+	// Delete
+	err = cs.Delete(ctx, c.ID)
+	if !assert.NoError(err) {
+		return
+	}
+	c2, err = cs.Get(ctx, c.ID)
+	if assert.NoError(err) {
+		assert.Nil(c2)
+	}
+
+	// Delete again
+	err = cs.Delete(ctx, c.ID)
+	assert.Equal(ErrNotFound, err)
+
+	// Update missing
+	err = cs.Store(ctx, c)
+	assert.Equal(ErrNotFound, err)
+
+	characters, err := cs.List(ctx, &CharacterFilters{SceneNumber: 3})
+	if assert.NoError(err) && assert.Len(characters, 4) {
+		assert.Equal(int64(1), characters[0].ID)
+	}
+}
@@ -0,0 +1,265 @@
+package vanilla
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pboyd/godbmodels/common"
+)
+
+// ErrInUse is returned by ActorStore.Delete when the actor still plays
+// characters.
+type ErrInUse struct {
+	ActorID int64
+
+	// Characters are the characters the actor plays.
+	Characters []*Character
+}
+
+func (e *ErrInUse) Error() string {
+	names := make([]string, len(e.Characters))
+	for i, c := range e.Characters {
+		names[i] = c.Name
+	}
+
+	return fmt.Sprintf("actor %d plays %d characters: %s", e.ActorID, len(e.Characters), strings.Join(names, ", "))
+}
+
+// Actor is one actor from the database.
+type Actor struct {
+	ID   int64
+	Name string
+}
+
+// ActorStore loads and updates actors in the database.
+type ActorStore struct {
+	db      DBTX
+	dialect *common.Dialect
+}
+
+// NewActorStore creates a new ActorStore. db can be a *sql.DB or a *sql.Tx.
+func NewActorStore(db DBTX, opts ...Option) *ActorStore {
+	o := newOptions(opts)
+	return &ActorStore{db: db, dialect: o.dialect}
+}
+
+// Get loads an actor from the database by ID.
+//
+// If no actor is found, Get returns a nil Actor and no error.
+func (as *ActorStore) Get(ctx context.Context, id int64) (*Actor, error) {
+	row := as.db.QueryRowContext(ctx, as.dialect.Rebind(`SELECT id, name FROM actors WHERE id = ?`), id)
+
+	var a Actor
+	err := row.Scan(&a.ID, &a.Name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	return &a, err
+}
+
+// Store saves an actor to the database. If the actor has an ID, it will be
+// updated. Otherwise, it will be inserted and the ID will be set.
+//
+// If the actor has an ID and it does not exist in the database, Store returns
+// ErrNotFound.
+func (as *ActorStore) Store(ctx context.Context, a *Actor) error {
+	if a.ID == 0 {
+		id, err := as.dialect.Insert(ctx, as.db, `INSERT INTO actors (name) VALUES (?)`, a.Name)
+		if err != nil {
+			return fmt.Errorf("insert actor: %w", err)
+		}
+
+		a.ID = id
+		return nil
+	}
+
+	res, err := as.db.ExecContext(ctx, as.dialect.Rebind(`UPDATE actors SET name = ? WHERE id = ?`), a.Name, a.ID)
+	if err != nil {
+		return fmt.Errorf("update actor: %w", err)
+	}
+
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteOption changes what ActorStore.Delete does with the actor's
+// characters.
+type DeleteOption func(*deleteOptions)
+
+type deleteOptions struct {
+	reassignTo int64
+	remove     bool
+}
+
+// ReassignCharacters moves the actor's characters to another actor before
+// the actor is deleted.
+func ReassignCharacters(actorID int64) DeleteOption {
+	return func(o *deleteOptions) {
+		o.reassignTo = actorID
+	}
+}
+
+// RemoveCharacters deletes the actor's characters, along with their scene
+// appearances and quotes.
+func RemoveCharacters() DeleteOption {
+	return func(o *deleteOptions) {
+		o.remove = true
+	}
+}
+
+// Delete removes an actor from the database.
+//
+// If the actor still plays any characters, Delete returns an *ErrInUse and
+// nothing is deleted, unless it's given ReassignCharacters or
+// RemoveCharacters, but not both. The characters and the actor are changed
+// in one transaction.
+//
+// If the actor does not exist in the database, Delete returns ErrNotFound.
+func (as *ActorStore) Delete(ctx context.Context, id int64, opts ...DeleteOption) error {
+	var o deleteOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.remove && o.reassignTo != 0 {
+		return errors.New("delete actor: can't both reassign and remove characters")
+	}
+
+	err := inTx(ctx, as.db, func(tx DBTX) error {
+		actors := NewActorStore(tx, WithDialect(as.dialect))
+		characters := NewCharacterStore(tx, WithDialect(as.dialect))
+
+		a, err := actors.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+		if a == nil {
+			return ErrNotFound
+		}
+
+		switch {
+		case o.remove:
+			err = as.remove(ctx, tx, id)
+		case o.reassignTo != 0:
+			err = as.reassign(ctx, tx, id, o.reassignTo)
+		default:
+			var list []*Character
+			list, err = characters.List(ctx, &CharacterFilters{ActorID: id})
+			if err == nil && len(list) > 0 {
+				err = &ErrInUse{ActorID: id, Characters: list}
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, as.dialect.Rebind(`DELETE FROM actors WHERE id = ?`), id)
+		return err
+	})
+
+	var inUse *ErrInUse
+	if err == nil || errors.Is(err, ErrNotFound) || errors.As(err, &inUse) {
+		return err
+	}
+
+	return fmt.Errorf("delete actor: %w", err)
+}
+
+// remove deletes an actor's characters. The quotes and scene appearances go
+// first, since the foreign keys don't cascade.
+func (as *ActorStore) remove(ctx context.Context, tx DBTX, actorID int64) error {
+	queries := []string{
+		`DELETE FROM quotes WHERE character_id IN (SELECT id FROM characters WHERE actor_id = ?)`,
+		`DELETE FROM scene_characters WHERE character_id IN (SELECT id FROM characters WHERE actor_id = ?)`,
+		`DELETE FROM characters WHERE actor_id = ?`,
+	}
+	for _, q := range queries {
+		_, err := tx.ExecContext(ctx, as.dialect.Rebind(q), actorID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (as *ActorStore) reassign(ctx context.Context, tx DBTX, from, to int64) error {
+	if from == to {
+		return fmt.Errorf("can't reassign characters from actor %d to itself", from)
+	}
+
+	a, err := NewActorStore(tx, WithDialect(as.dialect)).Get(ctx, to)
+	if err != nil {
+		return err
+	}
+	if a == nil {
+		return fmt.Errorf("can't reassign characters to actor %d, it does not exist", to)
+	}
+
+	_, err = tx.ExecContext(ctx, as.dialect.Rebind(`UPDATE characters SET actor_id = ? WHERE actor_id = ?`), to, from)
+	return err
+}
+
+// ActorFilters are used to filter the results of an ActorStore.List query.
+type ActorFilters struct {
+	// Name does a case-insensitive partial match on the actor name.
+	Name string
+
+	// PlaysMoreThan, if it's above zero, only matches actors who play more
+	// than this many characters.
+	PlaysMoreThan int
+}
+
+// List searches for actors in the database, in order of ID.
+//
+// If filters is nil, all actors are returned. Otherwise, the results are
+// filtered by the criteria in filters.
+func (as *ActorStore) List(ctx context.Context, filters *ActorFilters) ([]*Actor, error) {
+	var args []interface{}
+	query := "SELECT a.id, a.name FROM actors a"
+	where := []string{}
+
+	if filters != nil {
+		if filters.Name != "" {
+			cond, arg := as.dialect.ContainsFold("a.name", filters.Name)
+			where = append(where, cond)
+			args = append(args, arg)
+		}
+
+		if filters.PlaysMoreThan > 0 {
+			where = append(where, "(SELECT COUNT(*) FROM characters c WHERE c.actor_id = a.id) > ?")
+			args = append(args, filters.PlaysMoreThan)
+		}
+	}
+
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY a.id"
+
+	rows, err := as.db.QueryContext(ctx, as.dialect.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("list actors: %w", err)
+	}
+	defer rows.Close()
+
+	var actors []*Actor
+	for rows.Next() {
+		var a Actor
+		err := rows.Scan(&a.ID, &a.Name)
+		if err != nil {
+			return nil, fmt.Errorf("list actors: %w", err)
+		}
+
+		actors = append(actors, &a)
+	}
+
+	return actors, nil
+}
@@ -0,0 +1,234 @@
+package vanilla
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pboyd/godbmodels/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActors(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	as := NewActorStore(common.TestTx(t))
+	a := &Actor{Name: "Neil Innes"}
+
+	// Create
+	err := as.Store(ctx, a)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.NotZero(a.ID)
+
+	// Read
+	a2, err := as.Get(ctx, a.ID)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Equal(a, a2)
+
+	// Update
+	a.Name = "Neil James Innes"
+	err = as.Store(ctx, a)
+	if !assert.NoError(err) {
+		return
+	}
+	a2, err = as.Get(ctx, a.ID)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Equal(a, a2)
+
+	// Delete
+	err = as.Delete(ctx, a.ID)
+	if !assert.NoError(err) {
+		return
+	}
+	a2, err = as.Get(ctx, a.ID)
+	if assert.NoError(err) {
+		assert.Nil(a2)
+	}
+
+	// Delete again
+	err = as.Delete(ctx, a.ID)
+	assert.Equal(ErrNotFound, err)
+
+	// Update missing
+	err = as.Store(ctx, a)
+	assert.Equal(ErrNotFound, err)
+}
+
+func TestListActors(t *testing.T) {
+	cases := map[string]struct {
+		filters       *ActorFilters
+		expectedNames []string
+		expected      int
+	}{
+		"Nil": {
+			expected: 35,
+		},
+		"Name": {
+			filters:       &ActorFilters{Name: "TERRY"},
+			expectedNames: []string{"Terry Gilliam", "Terry Jones"},
+		},
+		"PlaysMoreThan": {
+			filters:       &ActorFilters{PlaysMoreThan: 7},
+			expectedNames: []string{"Eric Idle", "Michael Palin"},
+		},
+		"Both": {
+			filters:       &ActorFilters{Name: "terry", PlaysMoreThan: 6},
+			expectedNames: []string{"Terry Gilliam"},
+		},
+	}
+
+	as := NewActorStore(common.TestTx(t))
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			actors, err := as.List(context.Background(), tc.filters)
+			if !assert.NoError(err) {
+				return
+			}
+
+			if tc.expectedNames == nil {
+				assert.Len(actors, tc.expected)
+				return
+			}
+
+			names := make([]string, len(actors))
+			for i, a := range actors {
+				names[i] = a.Name
+			}
+			assert.Equal(tc.expectedNames, names)
+		})
+	}
+}
+
+func TestDeleteActor(t *testing.T) {
+	ctx := context.Background()
+	tx := common.TestTx(t)
+	as := NewActorStore(tx)
+	cs := NewCharacterStore(tx)
+
+	// Eric Idle plays 8 characters.
+	const idle = 3
+
+	countCharacters := func(t *testing.T, actorID int64) int {
+		list, err := cs.List(ctx, &CharacterFilters{ActorID: actorID})
+		if err != nil {
+			t.Fatalf("Error listing characters: %s", err)
+		}
+		return len(list)
+	}
+
+	t.Run("InUse", func(t *testing.T) {
+		assert := assert.New(t)
+		common.TestSavepoint(t, tx)
+
+		err := as.Delete(ctx, idle)
+		var inUse *ErrInUse
+		if assert.True(errors.As(err, &inUse), "%v", err) {
+			assert.Equal(int64(idle), inUse.ActorID)
+			assert.Len(inUse.Characters, 8)
+		}
+
+		a, err := as.Get(ctx, idle)
+		if assert.NoError(err) {
+			assert.NotNil(a)
+		}
+		assert.Equal(8, countCharacters(t, idle))
+	})
+
+	t.Run("Reassign", func(t *testing.T) {
+		assert := assert.New(t)
+		common.TestSavepoint(t, tx)
+
+		before := countCharacters(t, 2)
+		err := as.Delete(ctx, idle, ReassignCharacters(2))
+		if !assert.NoError(err) {
+			return
+		}
+
+		a, err := as.Get(ctx, idle)
+		if assert.NoError(err) {
+			assert.Nil(a)
+		}
+		assert.Equal(0, countCharacters(t, idle))
+		assert.Equal(before+8, countCharacters(t, 2))
+	})
+
+	t.Run("ReassignMissing", func(t *testing.T) {
+		assert := assert.New(t)
+		common.TestSavepoint(t, tx)
+
+		err := as.Delete(ctx, idle, ReassignCharacters(9999))
+		assert.Error(err)
+		assert.NotErrorIs(err, ErrNotFound)
+
+		// Nothing changed.
+		a, err := as.Get(ctx, idle)
+		if assert.NoError(err) {
+			assert.NotNil(a)
+		}
+		assert.Equal(8, countCharacters(t, idle))
+	})
+
+	t.Run("ReassignAndRemove", func(t *testing.T) {
+		assert := assert.New(t)
+		common.TestSavepoint(t, tx)
+
+		err := as.Delete(ctx, idle, ReassignCharacters(2), RemoveCharacters())
+		assert.Error(err)
+
+		// Nothing changed.
+		a, err := as.Get(ctx, idle)
+		if assert.NoError(err) {
+			assert.NotNil(a)
+		}
+		assert.Equal(8, countCharacters(t, idle))
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		assert := assert.New(t)
+		common.TestSavepoint(t, tx)
+
+		err := as.Delete(ctx, idle, RemoveCharacters())
+		if !assert.NoError(err) {
+			return
+		}
+		assert.Equal(0, countCharacters(t, idle))
+
+		var quotes int
+		err = tx.QueryRow(`SELECT COUNT(*) FROM quotes WHERE character_id NOT IN (SELECT id FROM characters)`).Scan(&quotes)
+		if assert.NoError(err) {
+			assert.Zero(quotes)
+		}
+	})
+}
+
+func TestDeleteActorDB(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	// A *sql.DB instead of a transaction, so Delete starts its own.
+	as := NewActorStore(common.TestDB(t))
+
+	err := as.Delete(ctx, 3, ReassignCharacters(9999))
+	assert.Error(err)
+	a, err := as.Get(ctx, 3)
+	if assert.NoError(err) {
+		assert.NotNil(a)
+	}
+
+	err = as.Delete(ctx, 3, RemoveCharacters())
+	if assert.NoError(err) {
+		a, err = as.Get(ctx, 3)
+		if assert.NoError(err) {
+			assert.Nil(a)
+		}
+	}
+}
@@ -6,10 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+
+	"github.com/pboyd/godbmodels/common"
 )
 
-// ErrNotFound is returned when updating or deleting a character that does not
-// exist in the database.
+// ErrNotFound is returned when updating or deleting a character or actor that
+// does not exist in the database.
 var ErrNotFound = errors.New("not found")
 
 // Character is one character from the database.
@@ -21,19 +23,22 @@ type Character struct {
 
 // CharacterStore loads and updates characters in the database.
 type CharacterStore struct {
-	db *sql.DB
+	db      DBTX
+	dialect *common.Dialect
 }
 
-// NewCharacterStore creates a new CharacterStore.
-func NewCharacterStore(db *sql.DB) *CharacterStore {
-	return &CharacterStore{db: db}
+// NewCharacterStore creates a new CharacterStore. db can be a *sql.DB or a
+// *sql.Tx.
+func NewCharacterStore(db DBTX, opts ...Option) *CharacterStore {
+	o := newOptions(opts)
+	return &CharacterStore{db: db, dialect: o.dialect}
 }
 
 // Get loads a character from the database by ID.
 //
 // If no character is found, Get returns a nil Character and no error.
 func (cs *CharacterStore) Get(ctx context.Context, id int64) (*Character, error) {
-	row := cs.db.QueryRowContext(ctx, `SELECT id, actor_id, name FROM characters WHERE id = $1`, id)
+	row := cs.db.QueryRowContext(ctx, cs.dialect.Rebind(`SELECT id, actor_id, name FROM characters WHERE id = ?`), id)
 
 	var c Character
 	err := row.Scan(&c.ID, &c.ActorID, &c.Name)
@@ -58,12 +63,17 @@ func (cs *CharacterStore) Store(ctx context.Context, c *Character) error {
 }
 
 func (cs *CharacterStore) insert(ctx context.Context, c *Character) error {
-	row := cs.db.QueryRowContext(ctx, `INSERT INTO characters (actor_id, name) VALUES ($1, $2) RETURNING id`, c.ActorID, c.Name)
-	return row.Scan(&c.ID)
+	id, err := cs.dialect.Insert(ctx, cs.db, `INSERT INTO characters (actor_id, name) VALUES (?, ?)`, c.ActorID, c.Name)
+	if err != nil {
+		return fmt.Errorf("insert character: %w", err)
+	}
+
+	c.ID = id
+	return nil
 }
 
 func (cs *CharacterStore) update(ctx context.Context, c *Character) error {
-	res, err := cs.db.ExecContext(ctx, `UPDATE characters SET actor_id = $1, name = $2 WHERE id = $3`, c.ActorID, c.Name, c.ID)
+	res, err := cs.db.ExecContext(ctx, cs.dialect.Rebind(`UPDATE characters SET actor_id = ?, name = ? WHERE id = ?`), c.ActorID, c.Name, c.ID)
 	if err != nil {
 		return fmt.Errorf("update character: %w", err)
 	}
@@ -80,7 +90,7 @@ func (cs *CharacterStore) update(ctx context.Context, c *Character) error {
 //
 // If the character does not exist in the database, Delete returns ErrNotFound.
 func (cs *CharacterStore) Delete(ctx context.Context, id int64) error {
-	res, err := cs.db.ExecContext(ctx, `DELETE FROM characters WHERE id = $1`, id)
+	res, err := cs.db.ExecContext(ctx, cs.dialect.Rebind(`DELETE FROM characters WHERE id = ?`), id)
 	if err != nil {
 		return fmt.Errorf("delete character: %w", err)
 	}
@@ -106,40 +116,207 @@ type CharacterFilters struct {
 
 	// SceneNumber filters by the scene that the character appears in.
 	SceneNumber int64
+
+	// ActorIDs matches characters played by any of these actors.
+	ActorIDs []int64
+
+	// ExcludeActorIDs leaves out characters played by any of these actors.
+	ExcludeActorIDs []int64
+
+	// SceneNumbers matches characters that appear in any of these scenes,
+	// or in every one of them if AllScenes is set.
+	SceneNumbers []int64
+
+	// AllScenes makes SceneNumbers match characters that appear in every
+	// listed scene.
+	AllScenes bool
+
+	// SceneFrom and SceneTo match characters that appear in at least one
+	// scene in the range, inclusive. Either can be zero to leave that end
+	// of the range open.
+	SceneFrom int64
+	SceneTo   int64
+
+	// OrderBy sorts the results. List defaults to common.OrderByID and
+	// ListPage defaults to common.OrderByName.
+	OrderBy common.OrderBy
+
+	// Descending reverses the order.
+	Descending bool
 }
 
 // List searches for characters in the database.
 //
-// If filters is nil, all characters are returned. Otherwise, the results are
-// filtered by the criteria in filters.
+// If filters is nil, all characters are returned, in order of ID. Otherwise,
+// the results are filtered and sorted by the criteria in filters. If
+// filters.OrderBy is invalid, the error wraps common.ErrInvalidOrder.
 func (cs *CharacterStore) List(ctx context.Context, filters *CharacterFilters) ([]*Character, error) {
-	var args []interface{}
-	query := "SELECT c.id, c.actor_id, c.name FROM characters c"
-	joins := []string{}
-	where := []string{}
-
-	if filters != nil {
-		if filters.ActorID != 0 {
-			where = append(where, "c.actor_id = ?")
-			args = append(args, filters.ActorID)
-		} else if filters.ActorName != "" {
-			joins = append(joins, "JOIN actors a ON a.id = c.actor_id")
-			where = append(where, "LOWER(a.name) LIKE ?")
-			args = append(args, "%"+strings.ToLower(filters.ActorName)+"%")
-		}
+	orderBy, err := filters.orderClause(common.OrderByID)
+	if err != nil {
+		return nil, err
+	}
 
-		if filters.Name != "" {
-			where = append(where, "LOWER(c.name) LIKE ?")
-			args = append(args, "%"+strings.ToLower(filters.Name)+"%")
-		}
+	joins, where, args := cs.filterClauses(filters)
+	return cs.list(ctx, listQuery(joins, where)+orderBy, args)
+}
+
+// CharacterPage is one page of characters from ListPage.
+type CharacterPage struct {
+	Characters []*Character
+
+	// HasMore is true if there are characters after this page.
+	HasMore bool
+
+	// NextCursor continues from the last character on this page. It's empty
+	// if HasMore is false.
+	NextCursor string
+}
+
+// ListPage is like List, but returns one page of the results. The default
+// order is by name.
+//
+// If page is invalid, the error wraps common.ErrInvalidPage or
+// common.ErrInvalidCursor.
+func (cs *CharacterStore) ListPage(ctx context.Context, filters *CharacterFilters, page common.Page) (*CharacterPage, error) {
+	orderBy, err := filters.orderClause(common.OrderByName)
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := page.After(filters.order(common.OrderByName))
+	if err != nil {
+		return nil, err
+	}
+
+	joins, where, args := cs.filterClauses(filters)
+	if after != nil {
+		where = append(where, "(c.name > ? OR (c.name = ? AND c.id > ?))")
+		args = append(args, after.Name, after.Name, after.ID)
+	}
+
+	// One extra row is fetched to find out if there's another page.
+	query := listQuery(joins, where) + orderBy + " LIMIT ? OFFSET ?"
+	args = append(args, page.Limit+1, page.Offset)
+
+	characters, err := cs.list(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &CharacterPage{}
+	p.Characters, p.HasMore, p.NextCursor = common.TrimPage(characters, page.Limit, func(c *Character) common.Cursor {
+		return common.Cursor{Name: c.Name, ID: c.ID}
+	})
+	return p, nil
+}
+
+// Count returns the number of characters that List would return for filters.
+// filters.OrderBy and Descending are ignored.
+func (cs *CharacterStore) Count(ctx context.Context, filters *CharacterFilters) (int, error) {
+	joins, where, args := cs.filterClauses(filters)
+	query := "SELECT COUNT(*) FROM (" + listQuery(joins, where) + ") l"
+
+	var n int
+	err := cs.db.QueryRowContext(ctx, cs.dialect.Rebind(query), args...).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("count characters: %w", err)
+	}
 
-		if filters.SceneNumber != 0 {
-			joins = append(joins, "JOIN scene_characters sc ON sc.character_id = c.id")
-			where = append(where, "sc.scene_id = ?")
-			args = append(args, filters.SceneNumber)
+	return n, nil
+}
+
+// CharacterList is a list of characters from ListWithFacets.
+type CharacterList struct {
+	Characters []*Character
+	Facets     common.Facets
+}
+
+// ListWithFacets is like List, but also counts the matching characters by
+// actor and by scene. The counts are grouped by the database.
+func (cs *CharacterStore) ListWithFacets(ctx context.Context, filters *CharacterFilters) (*CharacterList, error) {
+	characters, err := cs.List(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	joins, where, args := cs.filterClauses(filters)
+	base := listQuery(joins, where)
+
+	actors, err := cs.facet(ctx, "SELECT l.actor_id, COUNT(*) FROM ("+base+") l GROUP BY l.actor_id ORDER BY l.actor_id", args)
+	if err != nil {
+		return nil, fmt.Errorf("count characters by actor: %w", err)
+	}
+
+	scenes, err := cs.facet(ctx, "SELECT fsc.scene_id, COUNT(*) FROM ("+base+") l JOIN scene_characters fsc ON fsc.character_id = l.id GROUP BY fsc.scene_id ORDER BY fsc.scene_id", args)
+	if err != nil {
+		return nil, fmt.Errorf("count characters by scene: %w", err)
+	}
+
+	return &CharacterList{
+		Characters: characters,
+		Facets: common.Facets{
+			Actors: actors,
+			Scenes: scenes,
+		},
+	}, nil
+}
+
+// facet runs a query that returns IDs and counts.
+func (cs *CharacterStore) facet(ctx context.Context, query string, args []interface{}) ([]common.FacetCount, error) {
+	rows, err := cs.db.QueryContext(ctx, cs.dialect.Rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var facets []common.FacetCount
+	for rows.Next() {
+		var f common.FacetCount
+		err := rows.Scan(&f.ID, &f.Count)
+		if err != nil {
+			return nil, err
 		}
+
+		facets = append(facets, f)
+	}
+
+	return facets, rows.Err()
+}
+
+// order returns the order the results are sorted in, which is def unless
+// filters says otherwise.
+func (filters *CharacterFilters) order(def common.OrderBy) (common.OrderBy, bool) {
+	if filters == nil {
+		return def, false
+	}
+	return filters.OrderBy.Or(def), filters.Descending
+}
+
+// orderClause returns the ORDER BY clause for filters.
+func (filters *CharacterFilters) orderClause(def common.OrderBy) (string, error) {
+	order, desc := filters.order(def)
+	clause, err := common.OrderClause("c", order, desc)
+	if err != nil {
+		return "", err
 	}
 
+	return " ORDER BY " + strings.Join(clause, ", "), nil
+}
+
+// filterClauses returns the joins, WHERE conditions and arguments that apply
+// filters to a query on characters c.
+func (cs *CharacterStore) filterClauses(filters *CharacterFilters) (joins, where []string, args []interface{}) {
+	if filters == nil {
+		return
+	}
+
+	f := common.CharacterFilters(*filters)
+	return cs.dialect.CharacterFilterClauses(&f)
+}
+
+func listQuery(joins, where []string) string {
+	query := "SELECT c.id, c.actor_id, c.name FROM characters c"
+
 	if len(joins) > 0 {
 		query += " " + strings.Join(joins, " ")
 	}
@@ -148,7 +325,11 @@ func (cs *CharacterStore) List(ctx context.Context, filters *CharacterFilters) (
 		query += " WHERE " + strings.Join(where, " AND ")
 	}
 
-	rows, err := cs.db.QueryContext(ctx, query, args...)
+	return query
+}
+
+func (cs *CharacterStore) list(ctx context.Context, query string, args []interface{}) ([]*Character, error) {
+	rows, err := cs.db.QueryContext(ctx, cs.dialect.Rebind(query), args...)
 	if err != nil {
 		return nil, fmt.Errorf("list characters: %w", err)
 	}
@@ -0,0 +1,147 @@
+package vanilla
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// Scene is one scene from the database. The ID is the scene number.
+type Scene struct {
+	ID   int64
+	Name string
+}
+
+// Quote is a line spoken by a character in a scene.
+type Quote struct {
+	ID          int64
+	CharacterID int64
+	SceneID     int64
+	Text        string
+}
+
+// Include selects the relations GetDetail loads.
+type Include uint
+
+const (
+	// IncludeActor loads CharacterDetail.Actor.
+	IncludeActor Include = 1 << iota
+
+	// IncludeScenes loads CharacterDetail.Scenes, in scene order.
+	IncludeScenes
+
+	// IncludeQuotes loads CharacterDetail.Quotes, in order of ID.
+	IncludeQuotes
+
+	// IncludeAll loads every relation.
+	IncludeAll = IncludeActor | IncludeScenes | IncludeQuotes
+)
+
+// CharacterDetail is a character along with the actor who plays them, the
+// scenes they appear in and the things they say.
+//
+// Relations that weren't requested are left nil.
+type CharacterDetail struct {
+	Character
+
+	Actor  *Actor
+	Scenes []*Scene
+	Quotes []*Quote
+}
+
+// GetDetail loads a character and the relations selected by include.
+//
+// It uses one query for the character and actor, plus one for scenes and one
+// for quotes when they're included.
+//
+// If no character is found, GetDetail returns a nil CharacterDetail and no
+// error.
+func (cs *CharacterStore) GetDetail(ctx context.Context, id int64, include Include) (*CharacterDetail, error) {
+	d, err := cs.getDetail(ctx, id, include)
+	if d == nil || err != nil {
+		return nil, err
+	}
+
+	if include&IncludeScenes != 0 {
+		d.Scenes, err = cs.characterScenes(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("get character detail: %w", err)
+		}
+	}
+
+	if include&IncludeQuotes != 0 {
+		d.Quotes, err = cs.characterQuotes(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("get character detail: %w", err)
+		}
+	}
+
+	return d, nil
+}
+
+// getDetail loads the character, joining the actor if it's included.
+func (cs *CharacterStore) getDetail(ctx context.Context, id int64, include Include) (*CharacterDetail, error) {
+	var d CharacterDetail
+	var err error
+
+	if include&IncludeActor != 0 {
+		d.Actor = &Actor{}
+		err = cs.db.QueryRowContext(ctx, cs.dialect.Rebind(`SELECT c.id, c.actor_id, c.name, a.id, a.name FROM characters c JOIN actors a ON a.id = c.actor_id WHERE c.id = ?`), id).
+			Scan(&d.ID, &d.ActorID, &d.Name, &d.Actor.ID, &d.Actor.Name)
+	} else {
+		err = cs.db.QueryRowContext(ctx, cs.dialect.Rebind(`SELECT id, actor_id, name FROM characters WHERE id = ?`), id).
+			Scan(&d.ID, &d.ActorID, &d.Name)
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get character detail: %w", err)
+	}
+
+	return &d, nil
+}
+
+func (cs *CharacterStore) characterScenes(ctx context.Context, id int64) ([]*Scene, error) {
+	rows, err := cs.db.QueryContext(ctx, cs.dialect.Rebind(`SELECT s.id, s.name FROM scenes s JOIN scene_characters sc ON sc.scene_id = s.id WHERE sc.character_id = ? ORDER BY s.id`), id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	scenes := []*Scene{}
+	for rows.Next() {
+		var s Scene
+		err := rows.Scan(&s.ID, &s.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		scenes = append(scenes, &s)
+	}
+
+	return scenes, rows.Err()
+}
+
+func (cs *CharacterStore) characterQuotes(ctx context.Context, id int64) ([]*Quote, error) {
+	rows, err := cs.db.QueryContext(ctx, cs.dialect.Rebind(`SELECT id, character_id, scene_id, text FROM quotes WHERE character_id = ? ORDER BY id`), id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	quotes := []*Quote{}
+	for rows.Next() {
+		var q Quote
+		err := rows.Scan(&q.ID, &q.CharacterID, &q.SceneID, &q.Text)
+		if err != nil {
+			return nil, err
+		}
+
+		quotes = append(quotes, &q)
+	}
+
+	return quotes, rows.Err()
+}
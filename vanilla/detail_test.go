@@ -0,0 +1,99 @@
+package vanilla
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/pboyd/godbmodels/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingDB counts the queries run through it.
+type countingDB struct {
+	DBTX
+	queries int
+}
+
+func (db *countingDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	db.queries++
+	return db.DBTX.QueryContext(ctx, query, args...)
+}
+
+func (db *countingDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	db.queries++
+	return db.DBTX.QueryRowContext(ctx, query, args...)
+}
+
+func TestGetDetail(t *testing.T) {
+	db := &countingDB{DBTX: common.TestTx(t)}
+	cs := NewCharacterStore(db)
+
+	cases := map[string]struct {
+		include Include
+		actor   *Actor
+		scenes  int
+		quotes  []int64
+		queries int
+	}{
+		"None":   {queries: 1},
+		"Actor":  {include: IncludeActor, actor: &Actor{ID: 1, Name: "Graham Chapman"}, queries: 1},
+		"Scenes": {include: IncludeScenes, scenes: 16, queries: 2},
+		"Quotes": {include: IncludeQuotes, quotes: []int64{1, 5, 9}, queries: 2},
+		"All": {
+			include: IncludeAll,
+			actor:   &Actor{ID: 1, Name: "Graham Chapman"},
+			scenes:  16,
+			quotes:  []int64{1, 5, 9},
+			queries: 3,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			db.queries = 0
+
+			// King Arthur
+			d, err := cs.GetDetail(context.Background(), 1, tc.include)
+			if !assert.NoError(err) || !assert.NotNil(d) {
+				return
+			}
+			assert.Equal(tc.queries, db.queries)
+			assert.Equal(Character{ID: 1, ActorID: 1, Name: "King Arthur"}, d.Character)
+			assert.Equal(tc.actor, d.Actor)
+
+			if tc.include&IncludeScenes == 0 {
+				assert.Nil(d.Scenes)
+			} else if assert.Len(d.Scenes, tc.scenes) {
+				assert.Equal(&Scene{ID: 1, Name: "The Trouble With Swallows"}, d.Scenes[0])
+				assert.Equal(int64(23), d.Scenes[tc.scenes-1].ID)
+			}
+
+			var quotes []int64
+			for _, q := range d.Quotes {
+				assert.Equal(int64(1), q.CharacterID)
+				quotes = append(quotes, q.ID)
+			}
+			assert.Equal(tc.quotes, quotes)
+		})
+	}
+
+	t.Run("Missing", func(t *testing.T) {
+		d, err := cs.GetDetail(context.Background(), 9999, IncludeAll)
+		if assert.NoError(t, err) {
+			assert.Nil(t, d)
+		}
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		assert := assert.New(t)
+
+		// Brother Maynard's Brother has no quotes.
+		d, err := cs.GetDetail(context.Background(), 41, IncludeQuotes)
+		if assert.NoError(err) && assert.NotNil(d) {
+			assert.NotNil(d.Quotes)
+			assert.Empty(d.Quotes)
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package vanilla
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pboyd/godbmodels/common"
+	"github.com/pboyd/godbmodels/common/sqltest"
+)
+
+func TestDialects(t *testing.T) {
+	for _, d := range common.Dialects {
+		d := d
+		t.Run(d.Name, func(t *testing.T) {
+			ctx := context.Background()
+			db, rec := sqltest.Open(t)
+			cs := NewCharacterStore(db, WithDialect(d))
+
+			rec.Section("Get")
+			cs.Get(ctx, 1)
+
+			rec.Section("Insert")
+			cs.Store(ctx, &Character{ActorID: 1, Name: "Sir Not-Appearing-in-this-Film"})
+
+			rec.Section("Update")
+			cs.Store(ctx, &Character{ID: 82, ActorID: 2, Name: "Sir Maybe-Appearing-in-this-Film"})
+
+			rec.Section("Delete")
+			cs.Delete(ctx, 82)
+
+			rec.Section("List")
+			cs.List(ctx, nil)
+			cs.List(ctx, &CharacterFilters{ActorID: 3, Name: "Brother", SceneNumber: 3})
+			cs.List(ctx, &CharacterFilters{ActorName: "Sandy", Name: "Knight"})
+			cs.List(ctx, &CharacterFilters{Name: "Knight", OrderBy: common.OrderByActorName, Descending: true})
+			cs.List(ctx, &CharacterFilters{ActorIDs: []int64{1, 2}, ExcludeActorIDs: []int64{3}, SceneNumbers: []int64{1, 3}, AllScenes: true, SceneFrom: 10, SceneTo: 15})
+			cs.List(ctx, &CharacterFilters{SceneNumbers: []int64{1, 3}, SceneTo: 15})
+
+			rec.Section("List Page")
+			cs.ListPage(ctx, &CharacterFilters{ActorID: 3}, common.Page{Limit: 10, Offset: 20})
+			cs.ListPage(ctx, &CharacterFilters{SceneNumber: 3}, common.Page{Limit: 10, Cursor: common.EncodeCursor(common.Cursor{Name: "Dennis", ID: 20})})
+
+			as := NewActorStore(db, WithDialect(d))
+
+			rec.Section("Count")
+			cs.Count(ctx, &CharacterFilters{ActorName: "Palin", SceneNumber: 3})
+
+			rec.Section("List With Facets")
+			cs.ListWithFacets(ctx, &CharacterFilters{Name: "Knight"})
+
+			rec.Section("Actor Insert")
+			as.Store(ctx, &Actor{Name: "Neil Innes"})
+
+			rec.Section("Actor Update")
+			as.Store(ctx, &Actor{ID: 36, Name: "Neil James Innes"})
+
+			rec.Section("Actor Delete")
+			as.Delete(ctx, 36)
+
+			rec.Section("Actor List")
+			as.List(ctx, &ActorFilters{Name: "Terry", PlaysMoreThan: 6})
+
+			gs := NewGraphStore(db, WithDialect(d))
+
+			rec.Section("Actor Co-stars")
+			gs.ActorCoStars(ctx, 7)
+
+			rec.Section("Character Co-stars")
+			gs.CharacterCoStars(ctx, 1)
+
+			rec.Section("Shortest Path")
+			gs.ShortestPath(ctx, 12, 13)
+
+			sqltest.Golden(t, "dialect_"+d.Name, rec.String())
+		})
+	}
+}
@@ -0,0 +1,431 @@
+package vanilla
+
+import (
+	"context"
+	"database/sql"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pboyd/godbmodels/common"
+)
+
+// GraphStore queries the co-appearance graph: two characters are connected
+// when they appear in the same scene, and so are the actors who play them.
+type GraphStore struct {
+	db      DBTX
+	dialect *common.Dialect
+}
+
+// NewGraphStore creates a new GraphStore. db can be a *sql.DB or a *sql.Tx.
+func NewGraphStore(db DBTX, opts ...Option) *GraphStore {
+	o := newOptions(opts)
+	return &GraphStore{db: db, dialect: o.dialect}
+}
+
+// ActorCoStar is an actor who appears in a scene with another actor.
+type ActorCoStar struct {
+	Actor
+
+	// SharedScenes is the number of scenes the actors appear in together.
+	SharedScenes int
+}
+
+// CharacterCoStar is a character who appears in a scene with another
+// character.
+type CharacterCoStar struct {
+	Character
+
+	// SharedScenes is the number of scenes the characters appear in
+	// together.
+	SharedScenes int
+}
+
+// ActorCoStars returns the actors who appear in a scene with an actor, with
+// the most shared scenes first and then in order of ID.
+//
+// Actors who play more than one character are only counted once per scene,
+// and an actor is never their own co-star.
+func (gs *GraphStore) ActorCoStars(ctx context.Context, actorID int64) ([]*ActorCoStar, error) {
+	query := `SELECT a.id, a.name, COUNT(DISTINCT sc2.scene_id) AS shared
+FROM scene_characters sc1
+JOIN characters c1 ON c1.id = sc1.character_id
+JOIN scene_characters sc2 ON sc2.scene_id = sc1.scene_id
+JOIN characters c2 ON c2.id = sc2.character_id
+JOIN actors a ON a.id = c2.actor_id
+WHERE c1.actor_id = ? AND c2.actor_id <> c1.actor_id
+GROUP BY a.id, a.name
+ORDER BY shared DESC, a.id`
+
+	rows, err := gs.db.QueryContext(ctx, gs.dialect.Rebind(query), actorID)
+	if err != nil {
+		return nil, fmt.Errorf("list actor co-stars: %w", err)
+	}
+	defer rows.Close()
+
+	var costars []*ActorCoStar
+	for rows.Next() {
+		var c ActorCoStar
+		err := rows.Scan(&c.ID, &c.Name, &c.SharedScenes)
+		if err != nil {
+			return nil, fmt.Errorf("list actor co-stars: %w", err)
+		}
+
+		costars = append(costars, &c)
+	}
+
+	return costars, rows.Err()
+}
+
+// CharacterCoStars returns the characters who appear in a scene with a
+// character, with the most shared scenes first and then in order of ID.
+func (gs *GraphStore) CharacterCoStars(ctx context.Context, characterID int64) ([]*CharacterCoStar, error) {
+	query := `SELECT c.id, c.actor_id, c.name, COUNT(*) AS shared
+FROM scene_characters sc1
+JOIN scene_characters sc2 ON sc2.scene_id = sc1.scene_id
+JOIN characters c ON c.id = sc2.character_id
+WHERE sc1.character_id = ? AND sc2.character_id <> sc1.character_id
+GROUP BY c.id, c.actor_id, c.name
+ORDER BY shared DESC, c.id`
+
+	rows, err := gs.db.QueryContext(ctx, gs.dialect.Rebind(query), characterID)
+	if err != nil {
+		return nil, fmt.Errorf("list character co-stars: %w", err)
+	}
+	defer rows.Close()
+
+	var costars []*CharacterCoStar
+	for rows.Next() {
+		var c CharacterCoStar
+		err := rows.Scan(&c.ID, &c.ActorID, &c.Name, &c.SharedScenes)
+		if err != nil {
+			return nil, fmt.Errorf("list character co-stars: %w", err)
+		}
+
+		costars = append(costars, &c)
+	}
+
+	return costars, rows.Err()
+}
+
+// PathStep is one actor on a path between two actors.
+type PathStep struct {
+	Actor
+
+	// SceneID is a scene the actor shares with the previous actor on the
+	// path. It's zero for the first actor.
+	SceneID int64
+}
+
+// actorEdges is a CTE with a row for each pair of actors who share a scene,
+// in both directions, along with the first scene they share.
+const actorEdges = `edges(from_id, to_id, scene_id) AS (
+	SELECT c1.actor_id, c2.actor_id, MIN(sc1.scene_id)
+	FROM scene_characters sc1
+	JOIN scene_characters sc2 ON sc2.scene_id = sc1.scene_id
+	JOIN characters c1 ON c1.id = sc1.character_id
+	JOIN characters c2 ON c2.id = sc2.character_id
+	WHERE c1.actor_id <> c2.actor_id
+	GROUP BY c1.actor_id, c2.actor_id
+)`
+
+// ShortestPath finds the shortest chain of actors from one actor to another,
+// where each actor shares a scene with the next. The number of steps after
+// the first is the degree of separation.
+//
+// When there's more than one shortest path, the one through the lowest actor
+// IDs is returned. If the actors aren't connected, or either doesn't exist,
+// ShortestPath returns a nil path and no error.
+func (gs *GraphStore) ShortestPath(ctx context.Context, fromID, toID int64) ([]*PathStep, error) {
+	// component is every actor connected to the last one. Its rows are
+	// distinct actor IDs, so it's cheap, and walk only starts if the first
+	// actor is in it.
+	//
+	// walk follows paths back from the last actor, recording each actor in
+	// path and never visiting one twice. The ORDER BY makes SQLite's queue
+	// a priority queue, so shorter paths come out first, and paths of the
+	// same length come out in order of their (zero-padded) actor IDs. The
+	// first row to reach the first actor is the answer, and SQLite stops
+	// there because of the LIMIT.
+	query := `WITH RECURSIVE ` + actorEdges + `,
+component(actor_id) AS (
+	SELECT id FROM actors WHERE id = ?
+	UNION
+	SELECT e.to_id FROM component c JOIN edges e ON e.from_id = c.actor_id
+),
+walk(actor_id, depth, path, scenes) AS (
+	SELECT id, 0, printf(',%08d,', id), ''
+	FROM actors
+	WHERE id = ? AND ? IN (SELECT actor_id FROM component)
+	UNION ALL
+	SELECT e.to_id, w.depth + 1, w.path || printf('%08d,', e.to_id), w.scenes || e.scene_id || ','
+	FROM walk w
+	JOIN edges e ON e.from_id = w.actor_id
+	WHERE w.path NOT LIKE '%,' || printf('%08d', e.to_id) || ',%'
+		AND w.depth < (SELECT COUNT(*) FROM component)
+	ORDER BY 2, 3
+)
+SELECT path, scenes FROM walk WHERE actor_id = ? LIMIT 1`
+
+	var path, scenes string
+	err := gs.db.QueryRowContext(ctx, gs.dialect.Rebind(query), toID, toID, fromID, fromID).Scan(&path, &scenes)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("shortest path: %w", err)
+	}
+
+	// Both lists run from the last actor to the first, and sceneIDs[i] is
+	// the scene ids[i] shares with ids[i+1], the actor before it on the
+	// path.
+	ids, err := splitIDs(path)
+	if err != nil {
+		return nil, fmt.Errorf("shortest path: %w", err)
+	}
+	sceneIDs, err := splitIDs(scenes)
+	if err != nil {
+		return nil, fmt.Errorf("shortest path: %w", err)
+	}
+
+	names, err := gs.actorNames(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("shortest path: %w", err)
+	}
+
+	steps := make([]*PathStep, len(ids))
+	for i, id := range ids {
+		step := &PathStep{Actor: Actor{ID: id, Name: names[id]}}
+		if i < len(sceneIDs) {
+			step.SceneID = sceneIDs[i]
+		}
+		steps[len(ids)-1-i] = step
+	}
+
+	return steps, nil
+}
+
+// splitIDs parses a comma-separated list of IDs. Empty entries are skipped.
+func splitIDs(list string) ([]int64, error) {
+	var ids []int64
+	for _, s := range strings.Split(list, ",") {
+		if s == "" {
+			continue
+		}
+
+		id, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// actorNames loads the names of the actors in ids.
+func (gs *GraphStore) actorNames(ctx context.Context, ids []int64) (map[int64]string, error) {
+	cond, arg := gs.dialect.InIDs("id", ids)
+	rows, err := gs.db.QueryContext(ctx, gs.dialect.Rebind(`SELECT id, name FROM actors WHERE `+cond), arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := map[int64]string{}
+	for rows.Next() {
+		var (
+			id   int64
+			name string
+		)
+		err := rows.Scan(&id, &name)
+		if err != nil {
+			return nil, err
+		}
+
+		names[id] = name
+	}
+
+	return names, rows.Err()
+}
+
+// Graph is the actor co-appearance graph. Actors are the nodes, and an edge
+// connects every pair of actors who appear in a scene together.
+type Graph struct {
+	// Actors are every actor, in order of ID, including those who don't
+	// share a scene with anyone.
+	Actors []*Actor
+
+	Edges []*Edge
+}
+
+// Edge connects two actors who share at least one scene. From is always the
+// lower ID.
+type Edge struct {
+	From, To     int64
+	SharedScenes int
+}
+
+// Graph loads the whole actor co-appearance graph.
+func (gs *GraphStore) Graph(ctx context.Context) (*Graph, error) {
+	g := &Graph{}
+
+	rows, err := gs.db.QueryContext(ctx, `SELECT id, name FROM actors ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("load graph: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a Actor
+		err := rows.Scan(&a.ID, &a.Name)
+		if err != nil {
+			return nil, fmt.Errorf("load graph: %w", err)
+		}
+
+		g.Actors = append(g.Actors, &a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("load graph: %w", err)
+	}
+	rows.Close()
+
+	query := `SELECT c1.actor_id, c2.actor_id, COUNT(DISTINCT sc1.scene_id)
+FROM scene_characters sc1
+JOIN scene_characters sc2 ON sc2.scene_id = sc1.scene_id
+JOIN characters c1 ON c1.id = sc1.character_id
+JOIN characters c2 ON c2.id = sc2.character_id
+WHERE c1.actor_id < c2.actor_id
+GROUP BY c1.actor_id, c2.actor_id
+ORDER BY c1.actor_id, c2.actor_id`
+
+	rows, err = gs.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("load graph: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e Edge
+		err := rows.Scan(&e.From, &e.To, &e.SharedScenes)
+		if err != nil {
+			return nil, fmt.Errorf("load graph: %w", err)
+		}
+
+		g.Edges = append(g.Edges, &e)
+	}
+
+	return g, rows.Err()
+}
+
+// WriteDOT writes the graph in the Graphviz DOT language. Edges are labeled
+// and weighted with the number of shared scenes.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("graph costars {\n")
+	for _, a := range g.Actors {
+		fmt.Fprintf(&b, "\t%d [label=%s];\n", a.ID, dotQuote(a.Name))
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "\t%d -- %d [label=%d, weight=%d];\n", e.From, e.To, e.SharedScenes, e.SharedScenes)
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+// The GraphML document structure, for WriteGraphML.
+type (
+	graphML struct {
+		XMLName xml.Name     `xml:"http://graphml.graphdrawing.org/xmlns graphml"`
+		Keys    []graphMLKey `xml:"key"`
+		Graph   graphMLGraph `xml:"graph"`
+	}
+
+	graphMLKey struct {
+		ID   string `xml:"id,attr"`
+		For  string `xml:"for,attr"`
+		Name string `xml:"attr.name,attr"`
+		Type string `xml:"attr.type,attr"`
+	}
+
+	graphMLGraph struct {
+		ID          string        `xml:"id,attr"`
+		EdgeDefault string        `xml:"edgedefault,attr"`
+		Nodes       []graphMLNode `xml:"node"`
+		Edges       []graphMLEdge `xml:"edge"`
+	}
+
+	graphMLNode struct {
+		ID   string        `xml:"id,attr"`
+		Data []graphMLData `xml:"data"`
+	}
+
+	graphMLEdge struct {
+		Source string        `xml:"source,attr"`
+		Target string        `xml:"target,attr"`
+		Data   []graphMLData `xml:"data"`
+	}
+
+	graphMLData struct {
+		Key   string `xml:"key,attr"`
+		Value string `xml:",chardata"`
+	}
+)
+
+// WriteGraphML writes the graph as a GraphML document. Nodes have a "name"
+// attribute and edges have a "shared_scenes" attribute.
+func (g *Graph) WriteGraphML(w io.Writer) error {
+	doc := graphML{
+		Keys: []graphMLKey{
+			{ID: "name", For: "node", Name: "name", Type: "string"},
+			{ID: "shared_scenes", For: "edge", Name: "shared_scenes", Type: "int"},
+		},
+		Graph: graphMLGraph{ID: "costars", EdgeDefault: "undirected"},
+	}
+
+	for _, a := range g.Actors {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{
+			ID:   graphMLNodeID(a.ID),
+			Data: []graphMLData{{Key: "name", Value: a.Name}},
+		})
+	}
+
+	for _, e := range g.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{
+			Source: graphMLNodeID(e.From),
+			Target: graphMLNodeID(e.To),
+			Data:   []graphMLData{{Key: "shared_scenes", Value: strconv.Itoa(e.SharedScenes)}},
+		})
+	}
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	err = enc.Encode(doc)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+func graphMLNodeID(actorID int64) string {
+	return "a" + strconv.FormatInt(actorID, 10)
+}
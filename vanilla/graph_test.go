@@ -0,0 +1,238 @@
+package vanilla
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/pboyd/godbmodels/common"
+	"github.com/pboyd/godbmodels/common/fixtures"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActorCoStars(t *testing.T) {
+	assert := assert.New(t)
+	gs := NewGraphStore(common.TestTx(t))
+
+	// Connie Booth is only in scene 5.
+	costars, err := gs.ActorCoStars(context.Background(), 7)
+	if !assert.NoError(err) {
+		return
+	}
+
+	ids := make([]int64, len(costars))
+	for i, c := range costars {
+		ids[i] = c.ID
+		assert.Equal(1, c.SharedScenes)
+	}
+	assert.Equal([]int64{1, 2, 3, 4, 5, 6, 9, 10, 18}, ids)
+
+	// Graham Chapman plays several characters in some scenes, but each
+	// scene is counted once and he's never his own co-star.
+	costars, err = gs.ActorCoStars(context.Background(), 1)
+	if assert.NoError(err) && assert.NotEmpty(costars) {
+		assert.Equal(&ActorCoStar{Actor: Actor{ID: 5, Name: "Terry Jones"}, SharedScenes: 14}, costars[0])
+		for _, c := range costars {
+			assert.NotEqual(int64(1), c.ID)
+		}
+	}
+
+	costars, err = gs.ActorCoStars(context.Background(), 99)
+	if assert.NoError(err) {
+		assert.Empty(costars)
+	}
+}
+
+func TestCharacterCoStars(t *testing.T) {
+	assert := assert.New(t)
+	gs := NewGraphStore(common.TestTx(t))
+
+	costars, err := gs.CharacterCoStars(context.Background(), 1)
+	if !assert.NoError(err) || !assert.True(len(costars) > 3) {
+		return
+	}
+
+	assert.Equal([]*CharacterCoStar{
+		{Character: Character{ID: 28, ActorID: 5, Name: "Sir Bedevere"}, SharedScenes: 10},
+		{Character: Character{ID: 37, ActorID: 6, Name: "Sir Galahad the Pure"}, SharedScenes: 7},
+		{Character: Character{ID: 8, ActorID: 2, Name: "Sir Lancelot the Brave"}, SharedScenes: 6},
+	}, costars[:3])
+}
+
+func TestShortestPath(t *testing.T) {
+	cases := map[string]struct {
+		from, to int64
+		actors   []int64
+		scenes   []int64
+	}{
+		"Same": {
+			from:   7,
+			to:     7,
+			actors: []int64{7},
+			scenes: []int64{0},
+		},
+		"Direct": {
+			from:   1,
+			to:     2,
+			actors: []int64{1, 2},
+			scenes: []int64{0, 1},
+		},
+		"Two": {
+			from:   12,
+			to:     13,
+			actors: []int64{12, 1, 13},
+			scenes: []int64{0, 23, 10},
+		},
+		"Missing": {
+			from: 7,
+			to:   99,
+		},
+	}
+
+	gs := NewGraphStore(common.TestTx(t))
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			path, err := gs.ShortestPath(context.Background(), tc.from, tc.to)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var actors, scenes []int64
+			for _, s := range path {
+				actors = append(actors, s.ID)
+				scenes = append(scenes, s.SceneID)
+			}
+			assert.Equal(t, tc.actors, actors)
+			assert.Equal(t, tc.scenes, scenes)
+		})
+	}
+}
+
+func TestShortestPathDisconnected(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	tx := common.TestTx(t)
+
+	a := &Actor{Name: "Neil Innes's Understudy"}
+	if !assert.NoError(NewActorStore(tx).Store(ctx, a)) {
+		return
+	}
+
+	path, err := NewGraphStore(tx).ShortestPath(ctx, 1, a.ID)
+	if assert.NoError(err) {
+		assert.Nil(path)
+	}
+
+	// The other way, the search starts in the connected part of the graph.
+	path, err = NewGraphStore(tx).ShortestPath(ctx, a.ID, 1)
+	if assert.NoError(err) {
+		assert.Nil(path)
+	}
+}
+
+func TestShortestPathLarge(t *testing.T) {
+	assert := assert.New(t)
+	db := common.TestEmptyDB(t)
+
+	// A chain of actors, each sharing a scene with the next, and one more
+	// scene that links the first actor to the middle of the chain.
+	const n = 1000
+	actors := make([]*fixtures.Actor, n)
+	scenes := make([]*fixtures.Scene, n)
+	for i := range actors {
+		actors[i] = fixtures.NewActor().Create(t, db)
+		scenes[i] = fixtures.NewScene().Create(t, db)
+	}
+	shortcut := scenes[n-1]
+	for i, a := range actors {
+		var in []*fixtures.Scene
+		if i > 0 {
+			in = append(in, scenes[i-1])
+		}
+		if i < n-1 {
+			in = append(in, scenes[i])
+		}
+		if i == 0 || i == n/2 {
+			in = append(in, shortcut)
+		}
+		fixtures.NewCharacter().PlayedBy(a).InScenes(in...).Create(t, db)
+	}
+
+	path, err := NewGraphStore(db).ShortestPath(context.Background(), actors[0].ID, actors[n-1].ID)
+	if !assert.NoError(err) || !assert.Len(path, n/2+1) {
+		return
+	}
+
+	assert.Equal(actors[0].ID, path[0].ID)
+	assert.Zero(path[0].SceneID)
+	assert.Equal(actors[n/2].ID, path[1].ID)
+	assert.Equal(shortcut.ID, path[1].SceneID)
+	for i, step := range path[2:] {
+		assert.Equal(actors[n/2+1+i].ID, step.ID)
+		assert.Equal(scenes[n/2+i].ID, step.SceneID)
+	}
+}
+
+func TestGraph(t *testing.T) {
+	assert := assert.New(t)
+	g, err := NewGraphStore(common.TestTx(t)).Graph(context.Background())
+	if !assert.NoError(err) {
+		return
+	}
+
+	assert.Len(g.Actors, 35)
+	if assert.Len(g.Edges, 376) {
+		assert.Equal(&Edge{From: 1, To: 2, SharedScenes: 12}, g.Edges[0])
+	}
+	for _, e := range g.Edges {
+		assert.Less(e.From, e.To)
+	}
+
+	var dot bytes.Buffer
+	if assert.NoError(g.WriteDOT(&dot)) {
+		out := dot.String()
+		assert.True(strings.HasPrefix(out, "graph costars {\n\t1 [label=\"Graham Chapman\"];\n"))
+		assert.Contains(out, "\t1 -- 2 [label=12, weight=12];\n")
+		assert.True(strings.HasSuffix(out, "}\n"))
+	}
+
+	var buf bytes.Buffer
+	if !assert.NoError(g.WriteGraphML(&buf)) {
+		return
+	}
+
+	var doc struct {
+		Graph struct {
+			EdgeDefault string `xml:"edgedefault,attr"`
+			Nodes       []struct {
+				ID   string `xml:"id,attr"`
+				Name string `xml:"data"`
+			} `xml:"node"`
+			Edges []struct {
+				Source string `xml:"source,attr"`
+				Target string `xml:"target,attr"`
+				Shared int    `xml:"data"`
+			} `xml:"edge"`
+		} `xml:"graph"`
+	}
+	if !assert.NoError(xml.Unmarshal(buf.Bytes(), &doc)) {
+		return
+	}
+	assert.Equal("undirected", doc.Graph.EdgeDefault)
+	if assert.Len(doc.Graph.Nodes, 35) {
+		assert.Equal("a1", doc.Graph.Nodes[0].ID)
+		assert.Equal("Graham Chapman", doc.Graph.Nodes[0].Name)
+	}
+	if assert.Len(doc.Graph.Edges, 376) {
+		assert.Equal("a1", doc.Graph.Edges[0].Source)
+		assert.Equal("a2", doc.Graph.Edges[0].Target)
+		assert.Equal(12, doc.Graph.Edges[0].Shared)
+	}
+}
+
+func TestDOTQuote(t *testing.T) {
+	assert.Equal(t, `"Brother Maynard's \"Brother\" \\"`, dotQuote(`Brother Maynard's "Brother" \`))
+}